@@ -3,71 +3,211 @@ package config
 import (
 	"context"
 	"fmt"
+	"io"
+	"log"
 	"sync"
+	"time"
 
 	"github.com/Si40Code/kit/config/provider"
 	"github.com/knadh/koanf/v2"
 )
 
 var (
-	k               *koanf.Koanf
-	mu              sync.RWMutex
-	changeCallbacks []func()
-	lastSnapshot    map[string]interface{} = make(map[string]interface{})
+	k            = koanf.New(".")
+	mu           sync.RWMutex
+	lastSnapshot map[string]interface{} = make(map[string]interface{})
+
+	// remoteLayer 最近一次加载和推送的远程配置，仅重载本地配置源时合并到最上层；由 reloadMu 保护
+	remoteLayer map[string]interface{}
 )
 
 func Init(opts ...Option) error {
 	options := newOptions(opts...)
 
-	k = koanf.New(".")
+	// 停止上一次 Init 启动的监控，并为本次监控创建可取消的上下文
+	ctx := startLifecycle()
+	dispatcher.configure(options.asyncCallbacks, options.callbackTimeout)
+
+	reloadMu.Lock()
+	resetRemoteTracker()
+	newK, remote, err := load(ctx, options, "init")
+	if err != nil {
+		reloadMu.Unlock()
+		return err
+	}
+	remoteLayer = remote
+	mu.Lock()
+	k = newK
+	lastSnapshot = cloneMap(k.Raw())
+	mu.Unlock()
+	activeOpts.Store(options)
+	reloadMu.Unlock()
+
+	if options.remoteProvider != nil {
+		if c, ok := options.remoteProvider.(io.Closer); ok {
+			trackCloser(c)
+		}
+		watchRemote(ctx, options)
+	}
+
+	// 启动文件监控（监控所有配置文件）
+	if options.watchFile {
+		for _, filePath := range options.filePaths {
+			startWatcher(ctx, filePath)
+		}
+	}
+
+	// 启动信号触发的重载
+	if len(options.reloadSignals) > 0 {
+		startSignalReload(ctx, options.reloadSignals)
+	}
+
+	// 启动轮询检查（适用于无法使用 fsnotify 的环境）
+	if options.pollInterval > 0 {
+		startPolling(ctx, options.filePaths, options.pollInterval)
+	}
+
+	return nil
+}
+
+// load 按优先级依次加载所有配置源，返回新的 koanf 实例和其中的远程配置
+// operation 标识触发加载的操作，用于远程配置同步指标
+func load(ctx context.Context, options *options, operation string) (*koanf.Koanf, map[string]interface{}, error) {
+	nk, err := loadLocal(options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 4. 加载远程配置（最高优先级）
+	if options.remoteProvider == nil {
+		return nk, nil, nil
+	}
+	rk := koanf.New(".")
+	start := time.Now()
+	err = options.remoteProvider.Load(ctx, rk)
+	recordSync(options, operation, start, err)
+	if err != nil {
+		return nil, nil, fmt.Errorf("load remote config failed: %w", err)
+	}
+	remote := rk.Raw()
+	if err := nk.Load(provider.MapProvider(remote), nil); err != nil {
+		return nil, nil, fmt.Errorf("merge remote config failed: %w", err)
+	}
+	return nk, remote, nil
+}
+
+// loadLocal 加载默认值、文件和环境变量配置
+func loadLocal(options *options) (*koanf.Koanf, error) {
+	nk := koanf.New(".")
 
 	// 1. 加载默认配置（最低优先级）
 	if options.defaults != nil {
-		if err := provider.LoadDefaults(k, options.defaults); err != nil {
-			return fmt.Errorf("load default config failed: %w", err)
+		if err := provider.LoadDefaults(nk, options.defaults); err != nil {
+			return nil, fmt.Errorf("load default config failed: %w", err)
 		}
 	}
 
 	// 2. 加载文件配置（按顺序加载，后面的覆盖前面的）
 	for _, filePath := range options.filePaths {
-		if err := provider.LoadFile(k, filePath); err != nil {
-			return fmt.Errorf("load file config failed (%s): %w", filePath, err)
+		if err := provider.LoadFileWithFormat(nk, filePath, options.fileFormats[filePath]); err != nil {
+			return nil, fmt.Errorf("load file config failed (%s): %w", filePath, err)
 		}
 	}
 
 	// 3. 加载环境变量配置
 	if options.useEnv {
-		if err := provider.LoadEnv(k, options.envPrefix); err != nil {
-			return fmt.Errorf("load env config failed: %w", err)
+		if err := provider.LoadEnv(nk, options.envPrefix); err != nil {
+			return nil, fmt.Errorf("load env config failed: %w", err)
 		}
 	}
 
-	// 4. 加载远程配置（最高优先级）
-	if options.remoteProvider != nil {
-		if err := options.remoteProvider.Load(context.Background(), k); err != nil {
-			return fmt.Errorf("load remote config failed: %w", err)
-		}
-		go options.remoteProvider.Watch(context.Background(), func(newCfg map[string]interface{}) {
-			mu.Lock()
-			LogConfigDiff("apollo", lastSnapshot, newCfg)
-			k.Load(provider.MapProvider(newCfg), nil)
-			lastSnapshot = cloneMap(k.Raw())
-			mu.Unlock()
-			notifyChange()
-		})
-	}
+	return nk, nil
+}
 
-	// 5. 启动文件监控（监控所有配置文件）
-	if options.watchFile {
-		for _, filePath := range options.filePaths {
-			startWatcher(filePath)
+// watchRemote 启动远程配置监听，收到推送后合并到当前配置并通知订阅者
+func watchRemote(ctx context.Context, options *options) {
+	p := options.remoteProvider
+	name := providerName(p)
+
+	watchWG.Add(1)
+	go func() {
+		defer watchWG.Done()
+		err := p.Watch(ctx, func(newCfg map[string]interface{}) {
+			if !beginReload() {
+				return
+			}
+			defer reloadWG.Done()
+
+			start := time.Now()
+			err := applyChange(name, func(k *koanf.Koanf) error {
+				if err := k.Load(provider.MapProvider(newCfg), nil); err != nil {
+					return err
+				}
+				// 同步更新远程配置层，之后本地文件重载时保留推送的值
+				rk := koanf.New(".")
+				_ = rk.Load(provider.MapProvider(remoteLayer), nil)
+				if err := rk.Load(provider.MapProvider(newCfg), nil); err != nil {
+					return err
+				}
+				remoteLayer = rk.Raw()
+				return nil
+			})
+			recordSync(options, "push", start, err)
+			if err != nil {
+				log.Println("apply remote config failed:", err)
+			}
+		})
+		if err != nil {
+			log.Println("remote watch error:", err)
 		}
+	}()
+}
+
+// applyChange 在当前配置上执行修改，配置发生变化时记录差异并通知订阅者
+func applyChange(source string, fn func(k *koanf.Koanf) error) error {
+	reloadMu.Lock()
+	mu.Lock()
+	if err := fn(k); err != nil {
+		mu.Unlock()
+		reloadMu.Unlock()
+		return err
 	}
+	newCfg := k.Raw()
+	changed := len(diffConfig(lastSnapshot, newCfg)) > 0
+	LogConfigDiff(source, lastSnapshot, newCfg)
+	lastSnapshot = cloneMap(newCfg)
+	mu.Unlock()
+	reloadMu.Unlock()
 
-	lastSnapshot = cloneMap(k.Raw())
+	if changed {
+		notifyChange()
+	}
 	return nil
 }
 
+// Set 在运行时覆盖配置项，并通知配置变更订阅者
+// 覆盖值只保存在内存中，下一次 Reload 或文件/远程配置重载后会被配置源中的值替换
+func Set(path string, value interface{}) error {
+	return applyChange("override", func(k *koanf.Koanf) error {
+		return k.Set(path, value)
+	})
+}
+
+// Delete 在运行时删除配置项，并通知配置变更订阅者
+func Delete(path string) {
+	_ = applyChange("override", func(k *koanf.Koanf) error {
+		k.Delete(path)
+		return nil
+	})
+}
+
+// Get 读取原始配置值，不存在时返回 nil
+func Get(path string) interface{} {
+	mu.RLock()
+	defer mu.RUnlock()
+	return k.Get(path)
+}
+
 func GetString(path string) string {
 	mu.RLock()
 	defer mu.RUnlock()
@@ -161,14 +301,14 @@ func Unmarshal(path string, out interface{}) error {
 	return k.Unmarshal(path, out)
 }
 
-func OnChange(cb func()) {
-	changeCallbacks = append(changeCallbacks, cb)
+// OnChange 注册配置变更回调，返回取消订阅函数
+// 回调按注册顺序执行，panic 会被恢复并记录日志；可并发调用
+func OnChange(cb func()) func() {
+	return dispatcher.subscribe(cb)
 }
 
 func notifyChange() {
-	for _, cb := range changeCallbacks {
-		cb()
-	}
+	dispatcher.notify()
 }
 
 func cloneMap(src map[string]interface{}) map[string]interface{} {
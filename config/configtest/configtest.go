@@ -0,0 +1,54 @@
+// Package configtest 提供 config 包的测试辅助工具
+//
+// 注意：config 使用全局状态，使用这些辅助函数的测试不应调用 t.Parallel()。
+package configtest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Si40Code/kit/config"
+)
+
+// Set 在测试期间覆盖配置项，测试结束时通过 t.Cleanup 自动恢复原值（原本不存在则删除）
+func Set(t testing.TB, key string, value interface{}) {
+	t.Helper()
+
+	existed := config.Exists(key)
+	old := config.Get(key)
+	if err := config.Set(key, value); err != nil {
+		t.Fatalf("configtest: set %q failed: %v", key, err)
+	}
+
+	t.Cleanup(func() {
+		if existed {
+			_ = config.Set(key, old)
+		} else {
+			config.Delete(key)
+		}
+	})
+}
+
+// LoadYAML 将 YAML 字符串作为完整配置初始化 config，可附加其他选项（如 config.WithRemote）
+// 测试结束时自动调用 config.Close
+func LoadYAML(t testing.TB, content string, opts ...config.Option) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("configtest: write config file failed: %v", err)
+	}
+
+	opts = append([]config.Option{config.WithFile(path)}, opts...)
+	if err := config.Init(opts...); err != nil {
+		t.Fatalf("configtest: init config failed: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := config.Close(context.Background()); err != nil {
+			t.Errorf("configtest: close config failed: %v", err)
+		}
+	})
+}
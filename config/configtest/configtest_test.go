@@ -0,0 +1,82 @@
+package configtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Si40Code/kit/config"
+)
+
+func TestLoadYAMLAndSet(t *testing.T) {
+	LoadYAML(t, "app:\n  name: demo\n  port: 8080\n")
+
+	t.Run("override", func(t *testing.T) {
+		Set(t, "app.name", "override")
+		Set(t, "app.debug", true)
+
+		if got := config.GetString("app.name"); got != "override" {
+			t.Fatalf("expected override, got %s", got)
+		}
+		if !config.GetBool("app.debug") {
+			t.Fatal("expected app.debug to be set")
+		}
+	})
+
+	if got := config.GetString("app.name"); got != "demo" {
+		t.Fatalf("expected value to be restored, got %s", got)
+	}
+	if config.Exists("app.debug") {
+		t.Fatal("expected app.debug to be removed after cleanup")
+	}
+}
+
+func TestRemoteProviderPush(t *testing.T) {
+	remote := NewRemoteProvider(map[string]interface{}{"feature.enabled": false})
+	LoadYAML(t, "feature:\n  name: demo\n", config.WithRemote(remote))
+
+	if config.GetBool("feature.enabled") {
+		t.Fatal("expected remote value to be loaded")
+	}
+
+	changed := 0
+	unsubscribe := config.OnChange(func() { changed++ })
+	defer unsubscribe()
+
+	if err := remote.Push(map[string]interface{}{"feature": map[string]interface{}{"enabled": true}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if changed != 1 {
+		t.Fatalf("expected 1 change notification, got %d", changed)
+	}
+	if !config.GetBool("feature.enabled") || config.GetString("feature.name") != "demo" {
+		t.Fatal("expected pushed value to be merged into current config")
+	}
+}
+
+func TestRemoteStatus(t *testing.T) {
+	remote := NewRemoteProvider(nil)
+	LoadYAML(t, "app:\n  name: demo\n", config.WithRemote(remote))
+
+	st := config.Status()
+	if !st.Initialized || st.Remote == nil {
+		t.Fatalf("expected initialized status with remote, got %+v", st)
+	}
+	if st.Remote.LastSyncTime.IsZero() || st.Remote.ConsecutiveFailures != 0 {
+		t.Fatalf("expected successful initial sync, got %+v", st.Remote)
+	}
+
+	remote.SetLoadError(errors.New("unavailable"))
+	if err := config.Reload(context.Background()); err == nil {
+		t.Fatal("expected reload to fail")
+	}
+
+	st = config.Status()
+	if st.Remote.ConsecutiveFailures != 1 || st.Remote.LastError == nil {
+		t.Fatalf("expected one recorded failure, got %+v", st.Remote)
+	}
+	if config.GetString("app.name") != "demo" {
+		t.Fatal("expected current config to be kept after failed reload")
+	}
+}
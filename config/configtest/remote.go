@@ -0,0 +1,95 @@
+package configtest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Si40Code/kit/config/provider"
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+)
+
+// pushTimeout Push 等待 Watch 注册的最长时间
+const pushTimeout = 5 * time.Second
+
+// RemoteProvider 内存中的远程配置提供者，实现 provider.RemoteProvider
+// 可以通过 Push 模拟远程配置推送，用于测试 OnChange 相关逻辑
+type RemoteProvider struct {
+	mu        sync.Mutex
+	data      map[string]interface{}
+	loadErr   error
+	loads     int
+	onChange  func(map[string]interface{})
+	watching  chan struct{}
+	watchOnce sync.Once
+}
+
+var _ provider.RemoteProvider = (*RemoteProvider)(nil)
+
+// NewRemoteProvider 创建内存远程配置提供者，initial 的 key 可以使用 "." 表示嵌套
+func NewRemoteProvider(initial map[string]interface{}) *RemoteProvider {
+	if initial == nil {
+		initial = map[string]interface{}{}
+	}
+	return &RemoteProvider{
+		data:     maps.Unflatten(maps.Copy(initial), "."),
+		watching: make(chan struct{}),
+	}
+}
+
+// Load 实现 provider.RemoteProvider
+func (p *RemoteProvider) Load(ctx context.Context, k *koanf.Koanf) error {
+	p.mu.Lock()
+	p.loads++
+	err := p.loadErr
+	data := maps.Copy(p.data)
+	p.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	return k.Load(provider.MapProvider(data), nil)
+}
+
+// Watch 实现 provider.RemoteProvider，记录变更回调后立即返回
+func (p *RemoteProvider) Watch(ctx context.Context, onChange func(map[string]interface{})) error {
+	p.mu.Lock()
+	p.onChange = onChange
+	p.mu.Unlock()
+	p.watchOnce.Do(func() { close(p.watching) })
+	return nil
+}
+
+// Push 更新远程配置并同步触发变更回调，回调返回后 OnChange 订阅者已收到通知（同步分发模式下）
+// 如果 config 尚未开始监听该提供者，会等待最多 5 秒
+func (p *RemoteProvider) Push(cfg map[string]interface{}) error {
+	select {
+	case <-p.watching:
+	case <-time.After(pushTimeout):
+		return errors.New("configtest: remote provider is not being watched")
+	}
+
+	p.mu.Lock()
+	maps.Merge(maps.Unflatten(maps.Copy(cfg), "."), p.data)
+	onChange := p.onChange
+	p.mu.Unlock()
+
+	onChange(cfg)
+	return nil
+}
+
+// SetLoadError 设置后续 Load 调用返回的错误，传 nil 恢复正常
+func (p *RemoteProvider) SetLoadError(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.loadErr = err
+}
+
+// LoadCount 返回 Load 被调用的次数
+func (p *RemoteProvider) LoadCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.loads
+}
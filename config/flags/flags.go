@@ -0,0 +1,141 @@
+// Package flags 基于 config 包实现的特性开关
+//
+// 开关定义在配置中（文件或 Apollo 均可），默认位于 feature_flags 下：
+//
+//	feature_flags:
+//	  new_checkout:
+//	    enabled: true
+//	    percentage: 20          # 按用户 ID 灰度 20%
+//	    allow: ["user:1001", "tenant:acme"]
+//	    deny: ["user:2002"]
+//	    start: 2026-10-01T00:00:00Z
+//	    end: 2026-11-01T00:00:00Z
+//
+// 配置重载后开关规则自动更新。
+package flags
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Si40Code/kit/config"
+)
+
+// DefaultPrefix 默认的开关配置前缀
+const DefaultPrefix = "feature_flags"
+
+// Option 配置选项函数
+type Option func(*options)
+
+// options 配置选项结构体
+type options struct {
+	prefix string
+	now    func() time.Time
+}
+
+// WithPrefix 设置开关配置所在的配置前缀
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithClock 设置时间来源（用于测试时间窗口）
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// Flags 特性开关集合
+type Flags struct {
+	options     *options
+	mu          sync.RWMutex
+	rules       map[string]Rule
+	unsubscribe func()
+}
+
+// New 从配置中加载特性开关，并在配置变更时自动刷新
+// 需要在 config.Init 之后调用
+func New(opts ...Option) *Flags {
+	o := &options{
+		prefix: DefaultPrefix,
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	f := &Flags{options: o}
+	f.refresh()
+	f.unsubscribe = config.OnChange(f.refresh)
+	return f
+}
+
+// refresh 从配置中重新读取开关规则，解析失败时保留原有规则
+func (f *Flags) refresh() {
+	rules := make(map[string]Rule)
+	if config.Exists(f.options.prefix) {
+		if err := config.Unmarshal(f.options.prefix, &rules); err != nil {
+			log.Printf("[Flags] failed to parse feature flags (prefix: %s): %v", f.options.prefix, err)
+			return
+		}
+	}
+
+	f.mu.Lock()
+	f.rules = rules
+	f.mu.Unlock()
+}
+
+// Enabled 判断开关对 context 中的主体是否开启
+func (f *Flags) Enabled(ctx context.Context, name string) bool {
+	return f.Evaluate(ctx, name).Enabled
+}
+
+// Evaluate 计算开关状态并返回原因
+func (f *Flags) Evaluate(ctx context.Context, name string) Result {
+	f.mu.RLock()
+	rule, ok := f.rules[name]
+	f.mu.RUnlock()
+	if !ok {
+		return Result{Reason: ReasonNotFound}
+	}
+
+	subject, _ := SubjectFromContext(ctx)
+	return rule.evaluate(name, subject, f.options.now())
+}
+
+// Rule 返回开关的规则定义
+func (f *Flags) Rule(name string) (Rule, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	rule, ok := f.rules[name]
+	return rule, ok
+}
+
+// Close 停止跟随配置变更
+func (f *Flags) Close() {
+	if f.unsubscribe != nil {
+		f.unsubscribe()
+	}
+}
+
+var (
+	defaultFlags *Flags
+	defaultOnce  sync.Once
+)
+
+// Default 返回使用默认前缀的全局开关集合，首次调用时创建
+func Default() *Flags {
+	defaultOnce.Do(func() {
+		defaultFlags = New()
+	})
+	return defaultFlags
+}
+
+// Enabled 使用全局开关集合判断开关是否开启
+func Enabled(ctx context.Context, name string) bool {
+	return Default().Enabled(ctx, name)
+}
@@ -0,0 +1,100 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Si40Code/kit/config"
+)
+
+const flagsYAML = `
+feature_flags:
+  off:
+    enabled: false
+  full:
+    enabled: true
+  rollout:
+    enabled: true
+    percentage: 30
+    allow: ["user:vip"]
+    deny: ["tenant:blocked"]
+  window:
+    enabled: true
+    start: 2026-10-01T00:00:00Z
+    end: 2026-11-01T00:00:00Z
+`
+
+func TestEvaluate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	if err := os.WriteFile(path, []byte(flagsYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Init(config.WithFile(path)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = config.Close(context.Background()) })
+
+	now := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	f := New(WithClock(func() time.Time { return now }))
+	defer f.Close()
+
+	ctx := context.Background()
+	user := func(id, tenant string) context.Context {
+		return WithSubject(ctx, Subject{UserID: id, TenantID: tenant})
+	}
+
+	cases := []struct {
+		ctx    context.Context
+		name   string
+		reason Reason
+	}{
+		{ctx, "missing", ReasonNotFound},
+		{ctx, "off", ReasonDisabled},
+		{ctx, "full", ReasonEnabled},
+		{user("vip", ""), "rollout", ReasonAllowed},
+		{user("vip", "blocked"), "rollout", ReasonDenied},
+		{ctx, "rollout", ReasonNotInRollout},
+		{ctx, "window", ReasonEnabled},
+	}
+	for _, c := range cases {
+		if got := f.Evaluate(c.ctx, c.name); got.Reason != c.reason {
+			t.Errorf("%s: expected reason %s, got %s", c.name, c.reason, got.Reason)
+		}
+	}
+
+	now = time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	if got := f.Evaluate(ctx, "window"); got.Reason != ReasonOutOfWindow {
+		t.Errorf("window: expected out_of_window after end, got %s", got.Reason)
+	}
+
+	// 灰度结果稳定且比例接近配置值
+	enabled := 0
+	for i := 0; i < 10000; i++ {
+		c := user(fmt.Sprintf("user-%d", i), "")
+		first := f.Enabled(c, "rollout")
+		if first != f.Enabled(c, "rollout") {
+			t.Fatal("rollout result is not stable")
+		}
+		if first {
+			enabled++
+		}
+	}
+	if enabled < 2700 || enabled > 3300 {
+		t.Errorf("expected about 30%% rollout, got %d/10000", enabled)
+	}
+
+	// 配置重载后规则自动更新
+	if err := os.WriteFile(path, []byte("feature_flags:\n  off:\n    enabled: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !f.Enabled(ctx, "off") {
+		t.Error("expected flag to be enabled after reload")
+	}
+}
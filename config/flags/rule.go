@@ -0,0 +1,115 @@
+package flags
+
+import (
+	"hash/fnv"
+	"strings"
+	"time"
+)
+
+// 灰度维度
+const (
+	RolloutByUser   = "user"
+	RolloutByTenant = "tenant"
+)
+
+// Reason 评估结果的原因
+type Reason string
+
+const (
+	ReasonNotFound     Reason = "not_found"      // 未定义该开关
+	ReasonDisabled     Reason = "disabled"       // 开关被关闭
+	ReasonOutOfWindow  Reason = "out_of_window"  // 不在生效时间窗口内
+	ReasonDenied       Reason = "denied"         // 命中黑名单
+	ReasonAllowed      Reason = "allowed"        // 命中白名单
+	ReasonRollout      Reason = "rollout"        // 命中百分比灰度
+	ReasonNotInRollout Reason = "not_in_rollout" // 未命中百分比灰度
+	ReasonEnabled      Reason = "enabled"        // 全量开启
+)
+
+// Rule 单个特性开关的规则定义
+//
+// 评估顺序：enabled → 时间窗口 → 黑名单 → 白名单 → 百分比灰度。
+// allow / deny 中的条目可以写成 "user:<id>"、"tenant:<id>"，不带前缀时同时匹配用户 ID 和租户 ID。
+// 未配置 percentage 时视为 100%，配置为 0 时只有白名单生效。
+type Rule struct {
+	Enabled    bool      `koanf:"enabled"`
+	Percentage *float64  `koanf:"percentage"` // 0-100
+	RolloutBy  string    `koanf:"rollout_by"` // user（默认）或 tenant
+	Allow      []string  `koanf:"allow"`
+	Deny       []string  `koanf:"deny"`
+	Start      time.Time `koanf:"start"` // 生效开始时间（包含）
+	End        time.Time `koanf:"end"`   // 生效结束时间（不包含）
+}
+
+// Result 评估结果
+type Result struct {
+	Enabled bool
+	Reason  Reason
+}
+
+// evaluate 根据规则和主体计算开关状态
+func (r Rule) evaluate(name string, subject Subject, now time.Time) Result {
+	if !r.Enabled {
+		return Result{Reason: ReasonDisabled}
+	}
+
+	if (!r.Start.IsZero() && now.Before(r.Start)) || (!r.End.IsZero() && !now.Before(r.End)) {
+		return Result{Reason: ReasonOutOfWindow}
+	}
+
+	if matchList(r.Deny, subject) {
+		return Result{Reason: ReasonDenied}
+	}
+
+	if matchList(r.Allow, subject) {
+		return Result{Enabled: true, Reason: ReasonAllowed}
+	}
+
+	if r.Percentage == nil || *r.Percentage >= 100 {
+		return Result{Enabled: true, Reason: ReasonEnabled}
+	}
+
+	key := subject.UserID
+	if r.RolloutBy == RolloutByTenant {
+		key = subject.TenantID
+	}
+	if key == "" || *r.Percentage <= 0 {
+		return Result{Reason: ReasonNotInRollout}
+	}
+
+	if bucket(name, key) < *r.Percentage*100 {
+		return Result{Enabled: true, Reason: ReasonRollout}
+	}
+	return Result{Reason: ReasonNotInRollout}
+}
+
+// bucket 将主体稳定地映射到 [0, 10000) 区间，精度为 0.01%
+// 哈希中包含开关名称，避免同一批用户总是同时命中所有开关
+func bucket(name, key string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{'/'})
+	h.Write([]byte(key))
+	return float64(h.Sum32() % 10000)
+}
+
+// matchList 检查主体是否命中名单
+func matchList(list []string, subject Subject) bool {
+	for _, item := range list {
+		switch {
+		case strings.HasPrefix(item, "user:"):
+			if subject.UserID != "" && subject.UserID == strings.TrimPrefix(item, "user:") {
+				return true
+			}
+		case strings.HasPrefix(item, "tenant:"):
+			if subject.TenantID != "" && subject.TenantID == strings.TrimPrefix(item, "tenant:") {
+				return true
+			}
+		default:
+			if item != "" && (item == subject.UserID || item == subject.TenantID) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package flags
+
+import "context"
+
+// Subject 特性开关的评估主体
+type Subject struct {
+	UserID   string
+	TenantID string
+}
+
+type subjectKey struct{}
+
+// WithSubject 将评估主体放入 context，后续的 Enabled 调用会据此进行灰度计算和名单匹配
+func WithSubject(ctx context.Context, s Subject) context.Context {
+	return context.WithValue(ctx, subjectKey{}, s)
+}
+
+// SubjectFromContext 从 context 中读取评估主体
+func SubjectFromContext(ctx context.Context) (Subject, bool) {
+	if ctx == nil {
+		return Subject{}, false
+	}
+	s, ok := ctx.Value(subjectKey{}).(Subject)
+	return s, ok
+}
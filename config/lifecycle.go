@@ -0,0 +1,103 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+var (
+	lifecycleMu  sync.Mutex
+	watchCtx     context.Context
+	watchCancel  context.CancelFunc
+	watchWG      sync.WaitGroup // 文件监控、远程监听等后台 goroutine
+	reloadWG     sync.WaitGroup // 正在执行的配置重载
+	fileWatchers []*fsnotify.Watcher
+	closers      []io.Closer
+)
+
+// startLifecycle 创建新的监控上下文，并停止上一次 Init 启动的监控
+func startLifecycle() context.Context {
+	lifecycleMu.Lock()
+	defer lifecycleMu.Unlock()
+
+	if watchCancel != nil {
+		watchCancel()
+		closeResourcesLocked()
+	}
+	watchCtx, watchCancel = context.WithCancel(context.Background())
+	return watchCtx
+}
+
+// trackWatcher 记录需要在 Close 时关闭的文件监控器
+func trackWatcher(w *fsnotify.Watcher) {
+	lifecycleMu.Lock()
+	defer lifecycleMu.Unlock()
+	fileWatchers = append(fileWatchers, w)
+}
+
+// trackCloser 记录需要在 Close 时关闭的资源（如远程配置提供者）
+func trackCloser(c io.Closer) {
+	lifecycleMu.Lock()
+	defer lifecycleMu.Unlock()
+	closers = append(closers, c)
+}
+
+// beginReload 标记一次重载开始，配置已关闭时返回 false
+// 返回 true 时调用方必须在重载结束后调用 reloadWG.Done()
+func beginReload() bool {
+	lifecycleMu.Lock()
+	defer lifecycleMu.Unlock()
+	if watchCtx == nil || watchCtx.Err() != nil {
+		return false
+	}
+	reloadWG.Add(1)
+	return true
+}
+
+// Close 停止所有配置监控，关闭文件监控器和远程配置提供者，并等待正在执行的重载完成
+// ctx 用于控制等待时间，超时后返回 ctx.Err()；已读取的配置在 Close 后仍可访问
+func Close(ctx context.Context) error {
+	lifecycleMu.Lock()
+	if watchCancel != nil {
+		watchCancel()
+	}
+	err := closeResourcesLocked()
+	lifecycleMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		watchWG.Wait()
+		reloadWG.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		return errors.Join(err, ctx.Err())
+	}
+}
+
+// closeResourcesLocked 关闭已记录的资源，调用方需持有 lifecycleMu
+func closeResourcesLocked() error {
+	var errs []error
+	for _, w := range fileWatchers {
+		if err := w.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close file watcher failed: %w", err))
+		}
+	}
+	for _, c := range closers {
+		if err := c.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close remote provider failed: %w", err))
+		}
+	}
+	fileWatchers = nil
+	closers = nil
+	return errors.Join(errs...)
+}
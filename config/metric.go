@@ -0,0 +1,27 @@
+package config
+
+import "time"
+
+// MetricData 远程配置同步的指标数据
+type MetricData struct {
+	// 基础信息
+	Provider  string // 提供者名称，如 apollo
+	Operation string // 同步方式：init（初始化）、reload（重载）、push（远程推送）
+
+	// 同步结果
+	Success  bool          // 是否成功
+	Duration time.Duration // 同步耗时
+	Error    error         // 错误（如果有）
+
+	// 同步状态
+	Version             string        // 当前配置版本
+	ConsecutiveFailures int           // 连续失败次数
+	Staleness           time.Duration // 距最近一次成功同步的时间，从未成功时为 0
+}
+
+// MetricRecorder 远程配置同步指标记录器接口
+type MetricRecorder interface {
+	// RecordSync 记录一次远程配置同步
+	// 实现者可以将数据发送到 Prometheus、SigNoz 或其他监控系统
+	RecordSync(data MetricData)
+}
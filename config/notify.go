@@ -0,0 +1,152 @@
+package config
+
+import (
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// changeDispatcher 管理配置变更回调的注册与分发
+type changeDispatcher struct {
+	mu          sync.RWMutex
+	nextID      uint64
+	subscribers []*subscriber
+
+	async   bool
+	timeout time.Duration
+}
+
+// subscriber 单个配置变更订阅者
+type subscriber struct {
+	id      uint64
+	cb      func()
+	pending chan struct{} // 异步模式下的待处理通知（容量为 1，多次变更会合并）
+	done    chan struct{} // 取消订阅时关闭
+	start   sync.Once
+}
+
+var dispatcher = &changeDispatcher{}
+
+// configure 设置分发模式，async 为 false 时同步分发；timeout 为异步回调的超时告警时间，<= 0 表示不检查
+func (d *changeDispatcher) configure(async bool, timeout time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.async = async
+	d.timeout = timeout
+}
+
+// subscribe 注册回调，返回取消订阅函数
+func (d *changeDispatcher) subscribe(cb func()) func() {
+	d.mu.Lock()
+	d.nextID++
+	sub := &subscriber{
+		id:      d.nextID,
+		cb:      cb,
+		pending: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	d.subscribers = append(d.subscribers, sub)
+	d.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			d.unsubscribe(sub.id)
+			close(sub.done)
+		})
+	}
+}
+
+// unsubscribe 移除指定 id 的订阅者
+func (d *changeDispatcher) unsubscribe(id uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, sub := range d.subscribers {
+		if sub.id == id {
+			// 复制一份新切片，避免影响正在分发中的快照
+			subs := make([]*subscriber, 0, len(d.subscribers)-1)
+			subs = append(subs, d.subscribers[:i]...)
+			d.subscribers = append(subs, d.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// notify 按注册顺序通知所有订阅者
+func (d *changeDispatcher) notify() {
+	d.mu.RLock()
+	subs := d.subscribers
+	async, timeout := d.async, d.timeout
+	d.mu.RUnlock()
+
+	for _, sub := range subs {
+		if !async {
+			safeCall(sub.cb)
+			continue
+		}
+
+		sub.start.Do(func() { go sub.run(timeout) })
+		select {
+		case sub.pending <- struct{}{}:
+		default:
+			// 已有未处理的通知，回调执行时会读取最新配置，无需重复投递
+		}
+	}
+}
+
+// run 异步模式下串行执行单个订阅者的回调，上一次回调返回前不会开始下一次
+func (s *subscriber) run(timeout time.Duration) {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.pending:
+			if timeout <= 0 {
+				safeCall(s.cb)
+				continue
+			}
+			if !s.callWithTimeout(timeout) {
+				return
+			}
+		}
+	}
+}
+
+// callWithTimeout 执行回调，超过 timeout 时只输出日志并继续等待回调返回
+// 期间的通知合并到 pending 中，回调返回后再处理；取消订阅时返回 false
+func (s *subscriber) callWithTimeout(timeout time.Duration) bool {
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		safeCall(s.cb)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-finished:
+		return true
+	case <-s.done:
+		return false
+	case <-timer.C:
+		log.Printf("[Config] change callback #%d exceeded timeout %s", s.id, timeout)
+	}
+
+	select {
+	case <-finished:
+		return true
+	case <-s.done:
+		return false
+	}
+}
+
+// safeCall 执行回调并恢复 panic，避免单个回调影响配置重载
+func safeCall(cb func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[Config] change callback panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	cb()
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDispatcherOrderAndUnsubscribe(t *testing.T) {
+	d := &changeDispatcher{}
+
+	var order []int
+	d.subscribe(func() { order = append(order, 1) })
+	unsub := d.subscribe(func() { order = append(order, 2) })
+	d.subscribe(func() { panic("boom") })
+	d.subscribe(func() { order = append(order, 3) })
+
+	d.notify()
+	unsub()
+	unsub() // 重复取消订阅应安全
+	d.notify()
+
+	want := []int{1, 2, 3, 1, 3}
+	if len(order) != len(want) {
+		t.Fatalf("expected %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, order)
+		}
+	}
+}
+
+func TestDispatcherAsyncSlowSubscriber(t *testing.T) {
+	d := &changeDispatcher{}
+	d.configure(true, 20*time.Millisecond)
+
+	block := make(chan struct{})
+	defer close(block)
+	unsubSlow := d.subscribe(func() { <-block })
+	defer unsubSlow()
+
+	fast := make(chan struct{}, 1)
+	unsubFast := d.subscribe(func() { fast <- struct{}{} })
+	defer unsubFast()
+
+	// 慢回调一直阻塞，快回调仍能收到通知，说明 notify 和其他订阅者没有被阻塞
+	d.notify()
+	select {
+	case <-fast:
+	case <-time.After(time.Second):
+		t.Fatal("fast subscriber was not notified")
+	}
+}
+
+func TestDispatcherAsyncTimeoutKeepsSerial(t *testing.T) {
+	d := &changeDispatcher{}
+	d.configure(true, time.Millisecond)
+
+	var running, overlaps atomic.Int32
+	entered := make(chan struct{}, 10)
+	block := make(chan struct{})
+	unsub := d.subscribe(func() {
+		if running.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		defer running.Add(-1)
+		entered <- struct{}{}
+		<-block
+	})
+	defer unsub()
+
+	d.notify()
+	<-entered
+	// 等待超时后再通知，超时不应开始新的一次回调
+	time.Sleep(10 * time.Millisecond)
+	d.notify()
+	d.notify()
+	select {
+	case <-entered:
+		t.Fatal("callback started again before the previous call returned")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(block)
+	select {
+	case <-entered:
+	case <-time.After(time.Second):
+		t.Fatal("pending notification was not delivered after the callback returned")
+	}
+	if overlaps.Load() != 0 {
+		t.Errorf("callback ran concurrently with itself %d times", overlaps.Load())
+	}
+}
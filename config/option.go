@@ -1,6 +1,10 @@
 package config
 
 import (
+	"os"
+	"syscall"
+	"time"
+
 	"github.com/Si40Code/kit/config/provider"
 )
 
@@ -8,11 +12,24 @@ type Option func(*options)
 
 type options struct {
 	filePaths      []string
+	fileFormats    map[string]string // 显式指定格式的文件，key 为文件路径
 	useEnv         bool
 	envPrefix      string
 	watchFile      bool
 	remoteProvider provider.RemoteProvider
 	defaults       map[string]interface{}
+
+	// 变更回调分发配置
+	asyncCallbacks  bool
+	callbackTimeout time.Duration
+
+	// Metric 配置
+	enableMetric   bool
+	metricRecorder MetricRecorder
+
+	// 重载触发配置
+	reloadSignals []os.Signal
+	pollInterval  time.Duration
 }
 
 func newOptions(opts ...Option) *options {
@@ -37,6 +54,18 @@ func WithFiles(paths ...string) Option {
 	}
 }
 
+// WithFileFormat 按指定格式加载配置文件，用于没有有效扩展名的文件
+// format 可选值：yaml、json、toml、hcl、ini、properties、dotenv
+func WithFileFormat(path, format string) Option {
+	return func(o *options) {
+		o.filePaths = append(o.filePaths, path)
+		if o.fileFormats == nil {
+			o.fileFormats = make(map[string]string)
+		}
+		o.fileFormats[path] = format
+	}
+}
+
 func WithEnv(prefix string) Option {
 	return func(o *options) {
 		o.useEnv = true
@@ -69,3 +98,39 @@ func WithDefaultStruct(defaultStruct interface{}) Option {
 		o.defaults = map[string]interface{}{"_struct": defaultStruct}
 	}
 }
+
+// WithAsyncCallbacks 异步分发配置变更回调
+// 每个回调在独立的 goroutine 中串行执行，慢回调不会阻塞配置重载；
+// timeout > 0 时，回调执行超时会记录日志，下一次回调仍在其返回后执行
+func WithAsyncCallbacks(timeout time.Duration) Option {
+	return func(o *options) {
+		o.asyncCallbacks = true
+		o.callbackTimeout = timeout
+	}
+}
+
+// WithReloadSignal 收到指定信号时重新加载全部配置，未指定信号时默认使用 SIGHUP
+func WithReloadSignal(sigs ...os.Signal) Option {
+	return func(o *options) {
+		if len(sigs) == 0 {
+			sigs = []os.Signal{syscall.SIGHUP}
+		}
+		o.reloadSignals = append(o.reloadSignals, sigs...)
+	}
+}
+
+// WithPolling 按固定间隔检查配置文件的修改时间和内容哈希，发生变化时重新加载本地配置源，远程配置沿用当前值
+// 适用于 NFS、部分容器卷等无法使用 fsnotify 的环境
+func WithPolling(interval time.Duration) Option {
+	return func(o *options) {
+		o.pollInterval = interval
+	}
+}
+
+// WithMetric 启用远程配置同步指标
+func WithMetric(recorder MetricRecorder) Option {
+	return func(o *options) {
+		o.enableMetric = true
+		o.metricRecorder = recorder
+	}
+}
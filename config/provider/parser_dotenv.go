@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/parsers/dotenv"
+)
+
+// envNestDelim .env 变量名中表示层级的分隔符，单个 "_" 保留在 key 中（如 RETRY_COUNT -> retry_count）
+const envNestDelim = "__"
+
+// DotenvParser .env 格式解析器，实现 koanf.Parser
+// key 转为小写，"__" 视为层级分隔符，所有值均为字符串
+type DotenvParser struct{}
+
+// Dotenv 返回 .env 解析器
+func Dotenv() *DotenvParser {
+	return &DotenvParser{}
+}
+
+// Unmarshal 解析 .env 内容
+func (p *DotenvParser) Unmarshal(b []byte) (map[string]interface{}, error) {
+	raw, err := dotenv.Parser().Unmarshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]interface{}, len(raw))
+	names := make(map[string]string, len(raw))
+	for name, v := range raw {
+		key := envKey(name)
+		if prev, ok := names[key]; ok {
+			return nil, fmt.Errorf("dotenv: %q and %q both map to key %q", prev, name, key)
+		}
+		names[key] = name
+		out[key] = v
+	}
+	return unflattenKeys(out, "dotenv")
+}
+
+// Marshal 将配置序列化为 .env 格式，层级以 "__" 连接，key 转为大写
+func (p *DotenvParser) Marshal(o map[string]interface{}) ([]byte, error) {
+	flat, _ := maps.Flatten(o, nil, ".")
+	out := make(map[string]interface{}, len(flat))
+	for k, v := range flat {
+		out[strings.ToUpper(strings.ReplaceAll(k, ".", envNestDelim))] = v
+	}
+	return dotenv.Parser().Marshal(out)
+}
+
+// envKey 将 .env 变量名映射为配置 key，例如 DATABASE__MAX_OPEN -> database.max_open
+func envKey(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), envNestDelim, ".")
+}
@@ -0,0 +1,112 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/knadh/koanf/maps"
+)
+
+// INIParser INI 格式解析器，实现 koanf.Parser
+// [section] 下的 key 会被放到 section 下，section 名中的 "." 表示嵌套，所有值均为字符串
+type INIParser struct{}
+
+// INI 返回 INI 解析器
+func INI() *INIParser {
+	return &INIParser{}
+}
+
+// Unmarshal 解析 INI 内容
+func (p *INIParser) Unmarshal(b []byte) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+	section := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return nil, fmt.Errorf("ini line %d: malformed section header %q", lineNo, line)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		idx := strings.IndexAny(line, "=:")
+		if idx <= 0 {
+			return nil, fmt.Errorf("ini line %d: expected key = value, got %q", lineNo, line)
+		}
+
+		key := strings.TrimSpace(line[:idx])
+		value := unquoteINI(strings.TrimSpace(line[idx+1:]))
+		if section != "" {
+			key = section + "." + key
+		}
+		out[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return unflattenKeys(out, "ini")
+}
+
+// Marshal 将配置序列化为 INI 格式
+// 顶层的标量值写在文件开头，嵌套结构按顶层 key 分为 section
+func (p *INIParser) Marshal(o map[string]interface{}) ([]byte, error) {
+	var (
+		buf      bytes.Buffer
+		sections []string
+		globals  []string
+	)
+	for k, v := range o {
+		if _, ok := v.(map[string]interface{}); ok {
+			sections = append(sections, k)
+		} else {
+			globals = append(globals, k)
+		}
+	}
+	sort.Strings(globals)
+	sort.Strings(sections)
+
+	for _, k := range globals {
+		fmt.Fprintf(&buf, "%s = %v\n", k, o[k])
+	}
+
+	for _, s := range sections {
+		flat, _ := maps.Flatten(o[s].(map[string]interface{}), nil, ".")
+		keys := make([]string, 0, len(flat))
+		for k := range flat {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "[%s]\n", s)
+		for _, k := range keys {
+			fmt.Fprintf(&buf, "%s = %v\n", k, flat[k])
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// unquoteINI 去掉值两侧成对的引号
+func unquoteINI(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/knadh/koanf/maps"
+)
+
+// PropertiesParser Java .properties 格式解析器，实现 koanf.Parser
+// key 中的 "." 会被展开为嵌套结构，所有值均为字符串
+type PropertiesParser struct{}
+
+// Properties 返回 Java .properties 解析器
+func Properties() *PropertiesParser {
+	return &PropertiesParser{}
+}
+
+// Unmarshal 解析 .properties 内容
+func (p *PropertiesParser) Unmarshal(b []byte) (map[string]interface{}, error) {
+	out := make(map[string]interface{})
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var logical strings.Builder
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+
+		// 续行时不判断注释
+		if logical.Len() == 0 && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+
+		// 以奇数个反斜杠结尾表示续行
+		if trailingBackslashes(line)%2 == 1 {
+			logical.WriteString(line[:len(line)-1])
+			continue
+		}
+		logical.WriteString(line)
+
+		key, value, err := splitProperty(logical.String())
+		logical.Reset()
+		if err != nil {
+			return nil, fmt.Errorf("properties line %d: %w", lineNo, err)
+		}
+		out[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if logical.Len() > 0 {
+		key, value, err := splitProperty(logical.String())
+		if err != nil {
+			return nil, fmt.Errorf("properties line %d: %w", lineNo, err)
+		}
+		out[key] = value
+	}
+
+	return unflattenKeys(out, "properties")
+}
+
+// Marshal 将配置序列化为 .properties 格式（key 按字典序排列）
+func (p *PropertiesParser) Marshal(o map[string]interface{}) ([]byte, error) {
+	flat, _ := maps.Flatten(o, nil, ".")
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, k := range keys {
+		buf.WriteString(escapeProperty(k, true))
+		buf.WriteString("=")
+		buf.WriteString(escapeProperty(fmt.Sprint(flat[k]), false))
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// splitProperty 拆分逻辑行为 key 和 value
+// key 以第一个未转义的 '='、':' 或空白结束
+func splitProperty(line string) (string, string, error) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' {
+			i++
+			continue
+		}
+		if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+			end = i
+			break
+		}
+	}
+
+	key, err := unescapeProperty(line[:end])
+	if err != nil {
+		return "", "", err
+	}
+
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	value, err := unescapeProperty(rest)
+	if err != nil {
+		return "", "", err
+	}
+	return key, value, nil
+}
+
+// unescapeProperty 处理 .properties 转义序列
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i == len(s)-1 {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("malformed \\u escape in %q", s)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\u escape in %q", s)
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// escapeProperty 转义 .properties 中的特殊字符
+func escapeProperty(s string, isKey bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':', '#', '!':
+			if isKey || i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		case ' ':
+			if isKey || i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// unflattenKeys 将 "." 分隔的 key 展开为嵌套结构
+// 同一个 key 既有值又是其他 key 的前缀（如 server 和 server.port）时无法展开，返回错误而不是丢弃其中一个
+func unflattenKeys(flat map[string]interface{}, format string) (map[string]interface{}, error) {
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for i := 0; i < len(k); i++ {
+			if k[i] != '.' {
+				continue
+			}
+			if _, ok := flat[k[:i]]; ok {
+				return nil, fmt.Errorf("%s: key %q has a value and is also the parent of %q", format, k[:i], k)
+			}
+		}
+	}
+	return maps.Unflatten(flat, "."), nil
+}
+
+// trailingBackslashes 统计行尾连续反斜杠的数量
+func trailingBackslashes(s string) int {
+	n := 0
+	for i := len(s) - 1; i >= 0 && s[i] == '\\'; i-- {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/knadh/koanf/v2"
+)
+
+func TestLoadFileFormats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.properties": "# comment\n! another comment\n" +
+			"server.port = 8080\n" +
+			"server.name:demo\\\n    -service\n" +
+			"greeting=hello\\u0020world\n" +
+			"path\\ with\\ space=ok\n",
+		"app.ini": "; comment\nname = demo\n\n[database]\nhost = localhost\nuser = \"root\"\n\n[database.pool]\nsize: 10\n",
+		"app.hcl": "server {\n  port = 9090\n}\n",
+		"app.env": "DATABASE__HOST=db.local\nDATABASE__MAX_OPEN=20\nAPP__DEBUG=true\nRETRY_COUNT=3\n",
+		"noext":   "server:\n  port: 7070\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		file   string
+		format string
+		want   map[string]string
+	}{
+		{"app.properties", "", map[string]string{
+			"server.port":     "8080",
+			"server.name":     "demo-service",
+			"greeting":        "hello world",
+			"path with space": "ok",
+		}},
+		{"app.ini", "", map[string]string{
+			"name":               "demo",
+			"database.host":      "localhost",
+			"database.user":      "root",
+			"database.pool.size": "10",
+		}},
+		{"app.hcl", "", map[string]string{"server.port": "9090"}},
+		{"app.env", "", map[string]string{
+			"database.host":     "db.local",
+			"database.max_open": "20",
+			"app.debug":         "true",
+			"retry_count":       "3",
+		}},
+		{"noext", FormatYAML, map[string]string{"server.port": "7070"}},
+	}
+
+	for _, c := range cases {
+		k := koanf.New(".")
+		if err := LoadFileWithFormat(k, filepath.Join(dir, c.file), c.format); err != nil {
+			t.Fatalf("%s: load failed: %v", c.file, err)
+		}
+		for key, want := range c.want {
+			if got := k.String(key); got != want {
+				t.Errorf("%s: %s = %q, want %q", c.file, key, got, want)
+			}
+		}
+	}
+
+	if err := LoadFile(koanf.New("."), filepath.Join(dir, "noext")); err == nil {
+		t.Error("expected error for file without extension")
+	}
+}
+
+func TestPropertiesRoundTrip(t *testing.T) {
+	in := map[string]interface{}{
+		"server": map[string]interface{}{"name": "a=b: c", "port": "80"},
+	}
+	b, err := Properties().Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := Properties().Unmarshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := out["server"].(map[string]interface{})["name"]; got != "a=b: c" {
+		t.Errorf("round trip mismatch: %q", got)
+	}
+}
+
+func TestParserKeyConflict(t *testing.T) {
+	cases := []struct {
+		name   string
+		parser interface {
+			Unmarshal([]byte) (map[string]interface{}, error)
+		}
+		content string
+	}{
+		{"properties value first", Properties(), "server=x\nserver.port=80\n"},
+		{"properties child first", Properties(), "server.port=80\nserver=x\n"},
+		{"ini value first", INI(), "a = 1\n\n[a]\nb = 2\n"},
+		{"ini section first", INI(), "[a]\nb = 2\n\n[x]\n[a.b]\nc = 3\n"},
+		{"dotenv value and parent", Dotenv(), "DB=1\nDB__MAX=2\n"},
+		{"dotenv same key", Dotenv(), "DB__MAX=1\ndb__max=2\n"},
+	}
+	for _, c := range cases {
+		for i := 0; i < 20; i++ {
+			_, err := c.parser.Unmarshal([]byte(c.content))
+			if err == nil {
+				t.Fatalf("%s: expected conflict error", c.name)
+			}
+			if !strings.Contains(err.Error(), `key "`) {
+				t.Fatalf("%s: error should name the key: %v", c.name, err)
+			}
+		}
+	}
+
+	if _, err := Properties().Unmarshal([]byte("server.port=80\nserver-name=x\nserver.host=h\n")); err != nil {
+		t.Errorf("unexpected error for sibling keys: %v", err)
+	}
+	if _, err := Dotenv().Unmarshal([]byte("DB_MAX=1\nDB_MAX_OPEN=2\n")); err != nil {
+		t.Errorf("unexpected error for snake_case keys: %v", err)
+	}
+}
@@ -1,22 +1,48 @@
 package provider
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
 
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/parsers/hcl"
 	"github.com/knadh/koanf/parsers/json"
 	"github.com/knadh/koanf/parsers/toml"
 	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/env"
 	"github.com/knadh/koanf/providers/file"
-	"github.com/knadh/koanf/providers/rawbytes"
 	"github.com/knadh/koanf/providers/structs"
 	"github.com/knadh/koanf/v2"
 )
 
+// 支持的配置文件格式
+const (
+	FormatYAML       = "yaml"
+	FormatJSON       = "json"
+	FormatTOML       = "toml"
+	FormatHCL        = "hcl"
+	FormatINI        = "ini"
+	FormatProperties = "properties"
+	FormatDotenv     = "dotenv"
+)
+
 func LoadFile(k *koanf.Koanf, path string) error {
-	parser, err := getParser(path)
+	return LoadFileWithFormat(k, path, "")
+}
+
+// LoadFileWithFormat 按指定格式加载配置文件，format 为空时根据扩展名识别
+func LoadFileWithFormat(k *koanf.Koanf, path, format string) error {
+	var (
+		parser koanf.Parser
+		err    error
+	)
+	if format != "" {
+		parser, err = ParserForFormat(format)
+	} else {
+		parser, err = getParser(path)
+	}
 	if err != nil {
 		return err
 	}
@@ -33,8 +59,38 @@ func getParser(path string) (koanf.Parser, error) {
 		return json.Parser(), nil
 	case ".toml":
 		return toml.Parser(), nil
+	case ".hcl":
+		return hcl.Parser(true), nil
+	case ".ini":
+		return INI(), nil
+	case ".properties":
+		return Properties(), nil
+	case ".env":
+		return Dotenv(), nil
+	default:
+		return nil, fmt.Errorf("unsupported config file format: %s (supported: .yaml, .yml, .json, .toml, .hcl, .ini, .properties, .env)", ext)
+	}
+}
+
+// ParserForFormat 根据格式名称返回对应的解析器，用于没有有效扩展名的文件
+func ParserForFormat(format string) (koanf.Parser, error) {
+	switch strings.ToLower(format) {
+	case FormatYAML, "yml":
+		return yaml.Parser(), nil
+	case FormatJSON:
+		return json.Parser(), nil
+	case FormatTOML:
+		return toml.Parser(), nil
+	case FormatHCL:
+		return hcl.Parser(true), nil
+	case FormatINI:
+		return INI(), nil
+	case FormatProperties:
+		return Properties(), nil
+	case FormatDotenv, "env":
+		return Dotenv(), nil
 	default:
-		return nil, fmt.Errorf("unsupported config file format: %s (supported: .yaml, .yml, .json, .toml)", ext)
+		return nil, fmt.Errorf("unsupported config file format: %s (supported: yaml, json, toml, hcl, ini, properties, dotenv)", format)
 	}
 }
 
@@ -57,6 +113,22 @@ func LoadDefaults(k *koanf.Koanf, defaults map[string]interface{}) error {
 	return nil
 }
 
-func MapProvider(data map[string]interface{}) *rawbytes.RawBytes {
-	return rawbytes.Provider(nil)
+// MapProvider 将 map 作为 koanf 配置源，key 中的 "." 会被展开为嵌套结构
+func MapProvider(data map[string]interface{}) koanf.Provider {
+	return &mapProvider{data: data}
+}
+
+// mapProvider 基于内存 map 的配置源
+type mapProvider struct {
+	data map[string]interface{}
+}
+
+// ReadBytes 不支持，map 配置源无需解析器
+func (p *mapProvider) ReadBytes() ([]byte, error) {
+	return nil, errors.New("map provider does not support ReadBytes")
+}
+
+// Read 返回配置 map 的副本
+func (p *mapProvider) Read() (map[string]interface{}, error) {
+	return maps.Unflatten(maps.Copy(p.data), "."), nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
 
 	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/rawbytes"
@@ -12,9 +13,17 @@ import (
 )
 
 // ApolloProvider 实现 RemoteProvider 接口，用于从 Apollo 配置中心加载配置
+// agollo 客户端 Stop 后无法再次启动，Close 之后的 Load 和 Watch 会重新创建客户端，
+// 因此同一个提供者可以在 config.Close 或再次 config.Init 之后继续使用
 type ApolloProvider struct {
-	client    agollo.Agollo
+	StatusTracker
+
+	newClient func() (agollo.Agollo, error)
 	configKey string
+
+	mu     sync.Mutex
+	client agollo.Agollo // Close 后为 nil，下次使用时重新创建
+	stopCh chan struct{} // 关闭时停止正在运行的 Watch
 }
 
 // ApolloConfig Apollo 配置参数
@@ -32,84 +41,166 @@ func NewApolloProvider(cfg ApolloConfig) (*ApolloProvider, error) {
 		return nil, fmt.Errorf("Apollo AppID and ConfigKey are required")
 	}
 
-	// 创建 Apollo 客户端
-	client, err := agollo.New(
-		cfg.ServerURL,
-		cfg.AppID,
-		agollo.Cluster(cfg.Cluster),
-		agollo.AccessKey(cfg.AccessKey),
-		agollo.FailTolerantOnBackupExists(),
-		agollo.AutoFetchOnCacheMiss(),
-	)
+	newClient := func() (agollo.Agollo, error) {
+		client, err := agollo.New(
+			cfg.ServerURL,
+			cfg.AppID,
+			agollo.Cluster(cfg.Cluster),
+			agollo.AccessKey(cfg.AccessKey),
+			agollo.FailTolerantOnBackupExists(),
+			agollo.AutoFetchOnCacheMiss(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Apollo client: %w", err)
+		}
+		return client, nil
+	}
+
+	// 创建 Apollo 客户端，配置错误在创建提供者时返回
+	client, err := newClient()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Apollo client: %w", err)
+		return nil, err
 	}
 
 	return &ApolloProvider{
-		client:    client,
+		newClient: newClient,
 		configKey: cfg.ConfigKey,
+		client:    client,
 	}, nil
 }
 
+// getClient 返回当前的 Apollo 客户端，Close 之后重新创建
+func (p *ApolloProvider) getClient() (agollo.Agollo, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.clientLocked()
+}
+
+// clientLocked 返回当前的 Apollo 客户端，不存在时创建，调用方需持有 p.mu
+func (p *ApolloProvider) clientLocked() (agollo.Agollo, error) {
+	if p.client == nil {
+		client, err := p.newClient()
+		if err != nil {
+			return nil, err
+		}
+		p.client = client
+	}
+	return p.client, nil
+}
+
 // Load 从 Apollo 加载配置到 koanf
 func (p *ApolloProvider) Load(ctx context.Context, k *koanf.Koanf) error {
 	log.Println("[Apollo] Loading configuration from Apollo...")
 
+	client, err := p.getClient()
+	if err != nil {
+		p.RecordFailure(err)
+		return err
+	}
+
 	// 从 Apollo 获取配置
-	configs := p.client.GetNameSpace(p.configKey)
+	configs := client.GetNameSpace(p.configKey)
 	configStr, ok := configs["content"].(string)
 	if !ok {
-		return fmt.Errorf("invalid config content from Apollo: %+v", configs["content"])
+		err := fmt.Errorf("invalid config content from Apollo: %+v", configs["content"])
+		p.RecordFailure(err)
+		return err
 	}
 
 	if configStr == "" {
-		return fmt.Errorf("empty config content from Apollo, configKey: %s", p.configKey)
+		err := fmt.Errorf("empty config content from Apollo, configKey: %s", p.configKey)
+		p.RecordFailure(err)
+		return err
 	}
 
 	// 解析 YAML 配置并加载到 koanf
 	if err := k.Load(rawbytes.Provider([]byte(configStr)), yaml.Parser()); err != nil {
-		return fmt.Errorf("failed to parse Apollo config: %w", err)
+		err = fmt.Errorf("failed to parse Apollo config: %w", err)
+		p.RecordFailure(err)
+		return err
 	}
+	p.RecordSuccess(ContentVersion([]byte(configStr)))
 
 	log.Printf("[Apollo] Successfully loaded configuration from Apollo (configKey: %s)", p.configKey)
 	return nil
 }
 
 // Watch 监听 Apollo 配置变更（启动时加载一次，不进行热更新）
+// 阻塞直到 ctx 取消或提供者被 Close，config 在后台 goroutine 中调用，Close(ctx) 会等待其退出
 func (p *ApolloProvider) Watch(ctx context.Context, onChange func(map[string]interface{})) error {
 	log.Println("[Apollo] Starting Apollo watcher (no hot reload)...")
 
+	client, stopCh, err := p.startWatch()
+	if err != nil {
+		p.RecordFailure(err)
+		return err
+	}
+
 	// 启动 Apollo 监听
-	errorCh := p.client.Start()
-	watchCh := p.client.Watch()
-
-	// 启动 goroutine 处理 Apollo 事件，但不调用 onChange
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				log.Println("[Apollo] Context cancelled, stopping watcher")
-				return
-			case err := <-errorCh:
-				if err != nil {
-					log.Printf("[Apollo] Error from Apollo server: %v", err.Err)
-				}
-			case resp := <-watchCh:
-				if resp.Error == nil {
-					log.Printf("[Apollo] Configuration changed in Apollo (configKey: %s), but hot reload is disabled", p.configKey)
-					// 不调用 onChange，因为配置热更新被禁用
-				} else {
-					log.Printf("[Apollo] Error watching Apollo changes: %v", resp.Error)
-				}
+	errorCh := client.Start()
+	watchCh := client.Watch()
+
+	// 处理 Apollo 事件，但不调用 onChange
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("[Apollo] Context cancelled, stopping watcher")
+			return nil
+		case <-stopCh:
+			log.Println("[Apollo] Provider closed, stopping watcher")
+			return nil
+		case err := <-errorCh:
+			if err != nil {
+				log.Printf("[Apollo] Error from Apollo server: %v", err.Err)
+				p.RecordFailure(err.Err)
+			}
+		case resp := <-watchCh:
+			if resp.Error == nil {
+				// 配置未加载到 koanf，只记录收到的版本，LastSyncTime 和 Version 仍为最近一次应用的配置
+				content, _ := resp.NewValue["content"].(string)
+				p.RecordReceived(ContentVersion([]byte(content)))
+				log.Printf("[Apollo] Configuration changed in Apollo (configKey: %s), but hot reload is disabled", p.configKey)
+				// 不调用 onChange，因为配置热更新被禁用
+			} else {
+				log.Printf("[Apollo] Error watching Apollo changes: %v", resp.Error)
+				p.RecordFailure(resp.Error)
 			}
 		}
-	}()
+	}
+}
 
-	return nil
+// startWatch 返回 Watch 使用的客户端和停止通道，上一次未退出的 Watch 会被停止
+func (p *ApolloProvider) startWatch() (agollo.Agollo, chan struct{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	client, err := p.clientLocked()
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.stopCh != nil {
+		close(p.stopCh)
+	}
+	p.stopCh = make(chan struct{})
+	return client, p.stopCh, nil
 }
 
-// Close 关闭 Apollo 客户端
+// Name 返回提供者名称
+func (p *ApolloProvider) Name() string {
+	return "apollo"
+}
+
+// Close 停止正在运行的 Watch 和 Apollo 客户端的长轮询
+// 之后再次 Load 或 Watch 时会创建新的客户端
 func (p *ApolloProvider) Close() error {
-	// Apollo client doesn't have a Close method, so we just return nil
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopCh != nil {
+		close(p.stopCh)
+		p.stopCh = nil
+	}
+	if p.client != nil {
+		p.client.Stop()
+		p.client = nil
+	}
 	return nil
 }
@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/shima-park/agollo"
+)
+
+// fakeApollo 用于测试的 Apollo 客户端，内容和推送由测试控制
+type fakeApollo struct {
+	agollo.Agollo
+	content string
+	watchCh chan *agollo.ApolloResponse
+	errCh   chan *agollo.LongPollerError
+	stopped bool
+}
+
+func newFakeApollo(content string) *fakeApollo {
+	return &fakeApollo{
+		content: content,
+		watchCh: make(chan *agollo.ApolloResponse),
+		errCh:   make(chan *agollo.LongPollerError),
+	}
+}
+
+func (f *fakeApollo) Start() <-chan *agollo.LongPollerError { return f.errCh }
+func (f *fakeApollo) Stop()                                 { f.stopped = true }
+func (f *fakeApollo) Watch() <-chan *agollo.ApolloResponse  { return f.watchCh }
+func (f *fakeApollo) GetNameSpace(string) agollo.Configurations {
+	return agollo.Configurations{"content": f.content}
+}
+
+func TestApolloPushNotApplied(t *testing.T) {
+	client := newFakeApollo("server:\n  port: 8080\n")
+	p := &ApolloProvider{client: client, configKey: "application"}
+
+	if err := p.Load(context.Background(), koanf.New(".")); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	loaded := p.Status()
+	wantVersion := ContentVersion([]byte(client.content))
+	if loaded.Version != wantVersion || loaded.LastSyncTime.IsZero() || loaded.PendingVersion != "" {
+		t.Fatalf("status after load = %+v", loaded)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Watch(ctx, func(map[string]interface{}) {
+		t.Error("onChange should not be called")
+	})
+
+	pushed := "server:\n  port: 9090\n"
+	client.watchCh <- &agollo.ApolloResponse{NewValue: agollo.Configurations{"content": pushed}}
+	// 无缓冲通道，第二次发送成功说明第一次推送已处理完
+	client.watchCh <- &agollo.ApolloResponse{NewValue: agollo.Configurations{"content": pushed}}
+
+	st := p.Status()
+	if st.Version != wantVersion || !st.LastSyncTime.Equal(loaded.LastSyncTime) {
+		t.Errorf("push without applying changed synced state: %+v", st)
+	}
+	if st.PendingVersion != ContentVersion([]byte(pushed)) {
+		t.Errorf("PendingVersion = %q", st.PendingVersion)
+	}
+
+	// 重新加载后应用推送的版本
+	client.content = pushed
+	if err := p.Load(context.Background(), koanf.New(".")); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if st := p.Status(); st.PendingVersion != "" || st.Version != ContentVersion([]byte(pushed)) {
+		t.Errorf("status after reload = %+v", st)
+	}
+}
+
+func TestStatusTrackerReceivedSameVersion(t *testing.T) {
+	var tr StatusTracker
+	tr.RecordSuccess("v1")
+	tr.RecordReceived("v1")
+	if st := tr.Status(); st.PendingVersion != "" {
+		t.Errorf("PendingVersion = %q, want empty for already applied version", st.PendingVersion)
+	}
+}
+
+func TestApolloReuseAfterClose(t *testing.T) {
+	var clients []*fakeApollo
+	p := &ApolloProvider{
+		configKey: "application",
+		newClient: func() (agollo.Agollo, error) {
+			c := newFakeApollo("server:\n  port: 8080\n")
+			clients = append(clients, c)
+			return c, nil
+		},
+	}
+	if err := p.Load(context.Background(), koanf.New(".")); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- p.Watch(context.Background(), func(map[string]interface{}) {}) }()
+	// 无缓冲通道，发送成功说明 Watch 已在监听
+	clients[0].watchCh <- &agollo.ApolloResponse{NewValue: agollo.Configurations{"content": "x"}}
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not return after Close")
+	}
+	if !clients[0].stopped {
+		t.Error("client not stopped by Close")
+	}
+
+	// Close 之后再次使用时重新创建客户端，推送仍能被记录
+	if err := p.Load(context.Background(), koanf.New(".")); err != nil {
+		t.Fatalf("Load after Close: %v", err)
+	}
+	if len(clients) != 2 {
+		t.Fatalf("clients = %d, want a new client after Close", len(clients))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Watch(ctx, func(map[string]interface{}) {})
+	pushed := "server:\n  port: 9090\n"
+	clients[1].watchCh <- &agollo.ApolloResponse{NewValue: agollo.Configurations{"content": pushed}}
+	clients[1].watchCh <- &agollo.ApolloResponse{NewValue: agollo.Configurations{"content": pushed}}
+	if st := p.Status(); st.PendingVersion != ContentVersion([]byte(pushed)) {
+		t.Errorf("PendingVersion = %q after reuse", st.PendingVersion)
+	}
+}
@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Status 远程配置同步状态
+type Status struct {
+	LastSyncTime        time.Time // 最近一次成功同步的时间
+	LastError           error     // 最近一次同步错误
+	LastErrorTime       time.Time // 最近一次同步错误的时间
+	Version             string    // 当前已应用的配置版本（如 Apollo 配置内容摘要）
+	PendingVersion      string    // 配置中心已推送但尚未应用的版本，为空表示没有未应用的变更
+	ConsecutiveFailures int       // 连续失败次数，成功同步后清零
+}
+
+// StatusReporter 可选接口，远程配置提供者实现该接口后，config.Status() 会使用其上报的状态
+// 适用于提供者内部存在 config 无法感知的同步（如长轮询）的情况
+type StatusReporter interface {
+	Status() Status
+}
+
+// StatusTracker 并发安全的同步状态记录器，可嵌入到 RemoteProvider 实现中
+type StatusTracker struct {
+	mu     sync.RWMutex
+	status Status
+}
+
+// RecordSuccess 记录一次成功同步，version 为空时保留原版本
+func (t *StatusTracker) RecordSuccess(version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status.LastSyncTime = time.Now()
+	t.status.ConsecutiveFailures = 0
+	if version != "" {
+		t.status.Version = version
+		if t.status.PendingVersion == version {
+			t.status.PendingVersion = ""
+		}
+	}
+}
+
+// RecordReceived 记录收到但未应用的配置版本（如关闭热更新时的推送），不更新 LastSyncTime 和 Version
+func (t *StatusTracker) RecordReceived(version string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if version == t.status.Version {
+		t.status.PendingVersion = ""
+		return
+	}
+	t.status.PendingVersion = version
+}
+
+// RecordFailure 记录一次同步失败
+func (t *StatusTracker) RecordFailure(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status.LastError = err
+	t.status.LastErrorTime = time.Now()
+	t.status.ConsecutiveFailures++
+}
+
+// Status 返回当前同步状态
+func (t *StatusTracker) Status() Status {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.status
+}
+
+// ContentVersion 根据配置内容生成版本号（SHA-256 前 12 位），用于没有原生版本号的配置中心
+func ContentVersion(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])[:12]
+}
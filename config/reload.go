@@ -0,0 +1,191 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Si40Code/kit/config/provider"
+	"github.com/knadh/koanf/v2"
+)
+
+var (
+	reloadMu   sync.Mutex              // 串行化配置重载
+	activeOpts atomic.Pointer[options] // 最近一次 Init 使用的配置选项
+)
+
+// ErrNotInitialized 配置尚未初始化或已关闭
+var ErrNotInitialized = errors.New("config not initialized or already closed")
+
+// Reload 按 Init 时的配置源（默认值、文件、环境变量、远程配置）重新加载全部配置，SIGHUP 等信号触发的重载相同
+// 与文件监控、远程推送走相同的差异比对和变更通知流程；加载失败时保留当前配置
+func Reload(ctx context.Context) error {
+	if !beginReload() {
+		return ErrNotInitialized
+	}
+	defer reloadWG.Done()
+	return reload(ctx, "manual", true)
+}
+
+// reload 重新加载配置，配置发生变化时记录差异并通知订阅者
+// remote 为 false 时只重新加载本地配置源（默认值、文件、环境变量），远程配置使用最近一次加载和推送的结果，
+// 本地文件变更不受远程配置中心可用性的影响
+func reload(ctx context.Context, source string, remote bool) error {
+	opts := activeOpts.Load()
+	if opts == nil {
+		return ErrNotInitialized
+	}
+
+	reloadMu.Lock()
+	var (
+		newK *koanf.Koanf
+		err  error
+	)
+	if remote {
+		var layer map[string]interface{}
+		if newK, layer, err = load(ctx, opts, "reload"); err == nil {
+			remoteLayer = layer
+		}
+	} else {
+		newK, err = loadLocal(opts)
+		if err == nil && remoteLayer != nil {
+			err = newK.Load(provider.MapProvider(remoteLayer), nil)
+		}
+	}
+	if err != nil {
+		reloadMu.Unlock()
+		log.Printf("[Config] reload failed (source: %s): %v", source, err)
+		return err
+	}
+
+	mu.Lock()
+	newCfg := newK.Raw()
+	changed := len(diffConfig(lastSnapshot, newCfg)) > 0
+	LogConfigDiff(source, lastSnapshot, newCfg)
+	k = newK
+	lastSnapshot = cloneMap(newCfg)
+	mu.Unlock()
+	reloadMu.Unlock()
+
+	// 释放锁之后再通知，回调中可以安全地调用 Set、Reload 等方法
+	if changed {
+		notifyChange()
+	}
+	return nil
+}
+
+// startSignalReload 收到指定信号时重新加载配置
+func startSignalReload(ctx context.Context, sigs []os.Signal) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+
+	watchWG.Add(1)
+	go func() {
+		defer watchWG.Done()
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-ch:
+				if !beginReload() {
+					return
+				}
+				log.Printf("[Config] received signal %s, reloading configuration", sig)
+				_ = reload(ctx, "signal", true)
+				reloadWG.Done()
+			}
+		}
+	}()
+}
+
+// fileFingerprint 文件的修改时间、大小和内容哈希
+type fileFingerprint struct {
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+}
+
+// startPolling 定期检查配置文件的修改时间和内容哈希，发生变化时重新加载配置
+func startPolling(ctx context.Context, paths []string, interval time.Duration) {
+	prints := make(map[string]fileFingerprint, len(paths))
+	for _, path := range paths {
+		if fp, err := fingerprint(path, nil); err == nil {
+			prints[path] = fp
+		}
+	}
+
+	watchWG.Add(1)
+	go func() {
+		defer watchWG.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				changed := false
+				for _, path := range paths {
+					old, ok := prints[path]
+					var prev *fileFingerprint
+					if ok {
+						prev = &old
+					}
+					fp, err := fingerprint(path, prev)
+					if err != nil {
+						log.Printf("[Config] poll file failed (%s): %v", path, err)
+						continue
+					}
+					if !ok || fp.hash != old.hash {
+						changed = true
+					}
+					prints[path] = fp
+				}
+
+				if !changed {
+					continue
+				}
+				if !beginReload() {
+					return
+				}
+				_ = reload(ctx, "poll", false)
+				reloadWG.Done()
+			}
+		}
+	}()
+}
+
+// fingerprint 计算文件指纹；修改时间和大小均未变化时直接复用 prev，避免重复读取文件
+func fingerprint(path string, prev *fileFingerprint) (fileFingerprint, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileFingerprint{}, err
+	}
+	if prev != nil && info.ModTime().Equal(prev.modTime) && info.Size() == prev.size {
+		return *prev, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fileFingerprint{}, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fileFingerprint{}, err
+	}
+
+	fp := fileFingerprint{modTime: info.ModTime(), size: info.Size()}
+	copy(fp.hash[:], h.Sum(nil))
+	return fp, nil
+}
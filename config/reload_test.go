@@ -0,0 +1,157 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Si40Code/kit/config/provider"
+	"github.com/knadh/koanf/v2"
+)
+
+func TestReloadAndPolling(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("app:\n  name: v1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Init(WithFile(path), WithPolling(10*time.Millisecond)); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	t.Cleanup(func() { _ = Close(context.Background()) })
+
+	changed := make(chan string, 10)
+	unsubscribe := OnChange(func() { changed <- GetString("app.name") })
+	defer unsubscribe()
+
+	// 内容未变化时手动重载不应触发通知
+	if err := Reload(context.Background()); err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	select {
+	case v := <-changed:
+		t.Fatalf("unexpected change notification: %s", v)
+	default:
+	}
+
+	if err := os.WriteFile(path, []byte("app:\n  name: v2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case v := <-changed:
+		if v != "v2" {
+			t.Fatalf("expected v2, got %s", v)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("polling did not detect file change")
+	}
+
+	if err := Close(context.Background()); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if err := Reload(context.Background()); err != ErrNotInitialized {
+		t.Fatalf("expected ErrNotInitialized after close, got %v", err)
+	}
+}
+
+// countingRemote 记录 Load 次数的远程配置提供者
+type countingRemote struct {
+	mu    sync.Mutex
+	loads int
+	err   error
+	data  map[string]interface{}
+	push  func(map[string]interface{})
+}
+
+func (r *countingRemote) Load(ctx context.Context, k *koanf.Koanf) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loads++
+	if r.err != nil {
+		return r.err
+	}
+	return k.Load(provider.MapProvider(r.data), nil)
+}
+
+func (r *countingRemote) Watch(ctx context.Context, onChange func(map[string]interface{})) error {
+	r.mu.Lock()
+	r.push = onChange
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *countingRemote) state() (int, func(map[string]interface{})) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.loads, r.push
+}
+
+func TestFileReloadSkipsRemote(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("app:\n  name: v1\n  port: 80\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	remote := &countingRemote{data: map[string]interface{}{"app.port": 8080}}
+	if err := Init(WithFile(path), WithRemote(remote), WithPolling(10*time.Millisecond)); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	t.Cleanup(func() { _ = Close(context.Background()) })
+
+	// 等待远程监听启动后推送一次
+	var push func(map[string]interface{})
+	for deadline := time.Now().Add(time.Second); push == nil; {
+		if time.Now().After(deadline) {
+			t.Fatal("remote watch not started")
+		}
+		time.Sleep(time.Millisecond)
+		_, push = remote.state()
+	}
+	push(map[string]interface{}{"app.mode": "pushed"})
+
+	// 远程配置中心不可用时，本地文件的修改仍能生效
+	remote.mu.Lock()
+	remote.err = errors.New("apollo unavailable")
+	remote.mu.Unlock()
+
+	changed := make(chan string, 10)
+	defer OnChange(func() { changed <- GetString("app.name") })()
+
+	if err := os.WriteFile(path, []byte("app:\n  name: v2\n  port: 80\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case v := <-changed:
+		if v != "v2" {
+			t.Fatalf("expected v2, got %s", v)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("file change was not applied")
+	}
+
+	if loads, _ := remote.state(); loads != 1 {
+		t.Errorf("remote Load called %d times, want 1", loads)
+	}
+	if got := GetInt("app.port"); got != 8080 {
+		t.Errorf("remote value should stay on top after file reload, got %d", got)
+	}
+	if got := GetString("app.mode"); got != "pushed" {
+		t.Errorf("pushed value should survive file reload, got %q", got)
+	}
+
+	// 手动重载仍然请求远程配置，失败时保留当前配置
+	if err := Reload(context.Background()); err == nil {
+		t.Error("expected manual reload to fail while remote is unavailable")
+	}
+	if loads, _ := remote.state(); loads != 2 {
+		t.Errorf("remote Load called %d times after Reload, want 2", loads)
+	}
+	if got := GetString("app.name"); got != "v2" {
+		t.Errorf("app.name = %q after failed reload", got)
+	}
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Si40Code/kit/config/provider"
+)
+
+// RuntimeStatus 配置模块的运行状态
+type RuntimeStatus struct {
+	Initialized bool          // 是否已初始化
+	Remote      *RemoteStatus // 远程配置状态，未配置远程配置时为 nil
+}
+
+// RemoteStatus 远程配置提供者的同步状态
+type RemoteStatus struct {
+	Provider string // 提供者名称
+	provider.Status
+}
+
+// Stale 判断距最近一次成功同步是否已超过 maxAge（从未成功同步也视为过期）
+func (s RemoteStatus) Stale(maxAge time.Duration) bool {
+	return s.LastSyncTime.IsZero() || time.Since(s.LastSyncTime) > maxAge
+}
+
+var (
+	statusMu      sync.RWMutex
+	remoteTracker *provider.StatusTracker
+)
+
+// Status 返回配置模块的运行状态，可用于健康检查判断是否运行在过期的远程配置上
+func Status() RuntimeStatus {
+	opts := activeOpts.Load()
+
+	st := RuntimeStatus{Initialized: opts != nil}
+	if opts == nil || opts.remoteProvider == nil {
+		return st
+	}
+
+	st.Remote = &RemoteStatus{
+		Provider: providerName(opts.remoteProvider),
+		Status:   remoteStatus(opts.remoteProvider),
+	}
+	return st
+}
+
+// resetRemoteTracker 为新的远程配置提供者重置同步状态
+func resetRemoteTracker() {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	remoteTracker = &provider.StatusTracker{}
+}
+
+// remoteStatus 优先使用提供者自身上报的状态，否则使用 config 记录的状态
+func remoteStatus(p provider.RemoteProvider) provider.Status {
+	if r, ok := p.(provider.StatusReporter); ok {
+		return r.Status()
+	}
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+	if remoteTracker == nil {
+		return provider.Status{}
+	}
+	return remoteTracker.Status()
+}
+
+// recordSync 记录一次远程配置同步结果，并上报指标
+func recordSync(opts *options, operation string, start time.Time, err error) {
+	statusMu.RLock()
+	tracker := remoteTracker
+	statusMu.RUnlock()
+	if tracker != nil {
+		if err != nil {
+			tracker.RecordFailure(err)
+		} else {
+			tracker.RecordSuccess("")
+		}
+	}
+
+	if !opts.enableMetric || opts.metricRecorder == nil {
+		return
+	}
+
+	st := remoteStatus(opts.remoteProvider)
+	var staleness time.Duration
+	if !st.LastSyncTime.IsZero() {
+		staleness = time.Since(st.LastSyncTime)
+	}
+
+	opts.metricRecorder.RecordSync(MetricData{
+		Provider:            providerName(opts.remoteProvider),
+		Operation:           operation,
+		Success:             err == nil,
+		Duration:            time.Since(start),
+		Error:               err,
+		Version:             st.Version,
+		ConsecutiveFailures: st.ConsecutiveFailures,
+		Staleness:           staleness,
+	})
+}
+
+// providerName 返回提供者名称，提供者未实现 Name() 时使用类型名
+func providerName(p provider.RemoteProvider) string {
+	if n, ok := p.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+	return fmt.Sprintf("%T", p)
+}
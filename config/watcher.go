@@ -1,37 +1,42 @@
 package config
 
 import (
+	"context"
 	"log"
 
 	"github.com/fsnotify/fsnotify"
-	"github.com/knadh/koanf/parsers/yaml"
-	"github.com/knadh/koanf/providers/file"
 )
 
-func startWatcher(path string) {
+func startWatcher(ctx context.Context, path string) {
 	w, err := fsnotify.NewWatcher()
 	if err != nil {
 		log.Println("watcher error:", err)
 		return
 	}
+	trackWatcher(w)
 
+	watchWG.Add(1)
 	go func() {
+		defer watchWG.Done()
 		for {
 			select {
-			case event := <-w.Events:
+			case <-ctx.Done():
+				return
+			case event, ok := <-w.Events:
+				if !ok {
+					return
+				}
 				if event.Op&fsnotify.Write == fsnotify.Write {
-					mu.Lock()
-					if err := k.Load(file.Provider(path), yaml.Parser()); err == nil {
-						LogConfigDiff("file", lastSnapshot, k.Raw())
-						lastSnapshot = cloneMap(k.Raw())
-						mu.Unlock()
-						notifyChange()
-					} else {
-						log.Println("reload failed:", err)
-						mu.Unlock()
+					if !beginReload() {
+						return
 					}
+					_ = reload(ctx, "file", false)
+					reloadWG.Done()
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
 				}
-			case err := <-w.Errors:
 				log.Println("watch error:", err)
 			}
 		}
@@ -2,8 +2,13 @@ package httpclient
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/Si40Code/kit/logger"
+	"github.com/Si40Code/kit/logger/loggertest"
 )
 
 func TestNewClient(t *testing.T) {
@@ -94,6 +99,37 @@ func TestMetricRecorder(t *testing.T) {
 	_ = called
 }
 
+func TestRequestLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	log := loggertest.New(t)
+	client := New(WithLogger(log))
+
+	_, err := client.R(context.Background()).
+		SetHeader("Authorization", "Bearer secret").
+		Get(server.URL + "/users/1")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	started := log.AssertLogged(t, logger.InfoLevel, "HTTP request started", map[string]any{
+		"http.method": "GET",
+	})
+	headers, _ := started.Fields["http.request.headers"].(map[string]string)
+	if headers["Authorization"] != "******" {
+		t.Errorf("Authorization header not masked: %v", started.Fields["http.request.headers"])
+	}
+
+	log.AssertLogged(t, logger.WarnLevel, "client error", map[string]any{
+		"http.method":      "GET",
+		"http.url":         server.URL + "/users/1",
+		"http.status_code": 404,
+	})
+}
+
 type mockMetricRecorder struct {
 	recordFunc func(MetricData)
 }
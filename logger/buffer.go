@@ -0,0 +1,239 @@
+package logger
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// bufferKey context 中日志缓冲区的 key
+type bufferKey struct{}
+
+// bufferState 缓冲区状态
+type bufferState int
+
+const (
+	bufferCollecting  bufferState = iota // 收集中：低级别日志进入缓冲区
+	bufferPassthrough                    // 已因错误日志刷出：低级别日志直接写出
+	bufferClosed                         // 已 Flush 或 Discard：恢复正常记录
+)
+
+// Buffer 请求级别的日志缓冲区
+//
+// 作用域内低级别（默认 Debug、Info）的日志先暂存，不受 logger 级别限制；
+// 作用域结束时由调用方决定 Flush（写出）或 Discard（丢弃）。
+// 作用域内一旦出现 Error 及以上的日志，会先写出已缓冲的日志，之后的低级别日志直接写出。
+type Buffer struct {
+	mu      sync.Mutex
+	state   bufferState
+	entries []bufferedEntry
+	dropped int
+
+	maxLevel   Level
+	flushLevel Level
+	limit      int
+}
+
+// bufferedEntry 暂存的日志
+type bufferedEntry struct {
+	core   zapcore.Core
+	entry  zapcore.Entry
+	fields []zap.Field
+}
+
+// BufferOption 缓冲区选项
+type BufferOption func(*Buffer)
+
+// WithBufferLevel 设置进入缓冲区的最高级别，默认 InfoLevel
+func WithBufferLevel(level Level) BufferOption {
+	return func(b *Buffer) {
+		b.maxLevel = level
+	}
+}
+
+// WithBufferFlushLevel 设置触发自动写出的级别，默认 ErrorLevel
+func WithBufferFlushLevel(level Level) BufferOption {
+	return func(b *Buffer) {
+		b.flushLevel = level
+	}
+}
+
+// WithBufferLimit 设置缓冲区最多保存的日志条数，默认 1000，超出部分丢弃并在写出时提示
+func WithBufferLimit(n int) BufferOption {
+	return func(b *Buffer) {
+		b.limit = n
+	}
+}
+
+// BeginBuffer 开启日志缓冲作用域，返回携带缓冲区的 context
+//
+//	ctx, buf := logger.BeginBuffer(ctx)
+//	defer func() {
+//		if err != nil {
+//			buf.Flush()
+//		} else {
+//			buf.Discard()
+//		}
+//	}()
+func BeginBuffer(ctx context.Context, opts ...BufferOption) (context.Context, *Buffer) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	b := &Buffer{
+		maxLevel:   InfoLevel,
+		flushLevel: ErrorLevel,
+		limit:      1000,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return context.WithValue(ctx, bufferKey{}, b), b
+}
+
+// bufferFromContext 返回 context 中的缓冲区
+func bufferFromContext(ctx context.Context) *Buffer {
+	if ctx == nil {
+		return nil
+	}
+	b, _ := ctx.Value(bufferKey{}).(*Buffer)
+	return b
+}
+
+// Flush 写出缓冲的日志并结束作用域，之后的日志恢复正常记录
+func (b *Buffer) Flush() {
+	b.mu.Lock()
+	entries, dropped := b.entries, b.dropped
+	b.entries, b.dropped = nil, 0
+	b.state = bufferClosed
+	b.mu.Unlock()
+
+	writeBuffered(entries, dropped)
+}
+
+// Discard 丢弃缓冲的日志并结束作用域，之后的日志恢复正常记录
+func (b *Buffer) Discard() {
+	b.mu.Lock()
+	b.entries, b.dropped = nil, 0
+	b.state = bufferClosed
+	b.mu.Unlock()
+}
+
+// Len 返回当前缓冲的日志条数
+func (b *Buffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.entries)
+}
+
+// handles 判断日志是否由缓冲区处理；达到自动写出级别时先写出已缓冲的日志
+func (b *Buffer) handles(level Level) bool {
+	b.mu.Lock()
+	if b.state == bufferClosed {
+		b.mu.Unlock()
+		return false
+	}
+	if level <= b.maxLevel {
+		b.mu.Unlock()
+		return true
+	}
+	if level < b.flushLevel || b.state != bufferCollecting {
+		b.mu.Unlock()
+		return false
+	}
+
+	entries, dropped := b.entries, b.dropped
+	b.entries, b.dropped = nil, 0
+	b.state = bufferPassthrough
+	b.mu.Unlock()
+
+	writeBuffered(entries, dropped)
+	return false
+}
+
+// collects 判断日志级别是否会被缓冲，不触发自动写出
+func (b *Buffer) collects(level Level) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state != bufferClosed && level <= b.maxLevel
+}
+
+// add 暂存日志，已自动写出时直接写出
+func (b *Buffer) add(e bufferedEntry) {
+	b.mu.Lock()
+	switch b.state {
+	case bufferCollecting:
+		if len(b.entries) < b.limit {
+			b.entries = append(b.entries, e)
+		} else {
+			b.dropped++
+		}
+		b.mu.Unlock()
+		return
+	case bufferClosed:
+		b.mu.Unlock()
+		return
+	}
+	b.mu.Unlock()
+
+	writeBuffered([]bufferedEntry{e}, 0)
+}
+
+// writeBuffered 按原始顺序写出缓冲的日志，仍受各输出自身级别的限制
+func writeBuffered(entries []bufferedEntry, dropped int) {
+	for _, e := range entries {
+		if ce := e.core.Check(e.entry, nil); ce != nil {
+			ce.Write(e.fields...)
+		}
+	}
+
+	if dropped > 0 && len(entries) > 0 {
+		last := entries[len(entries)-1]
+		ent := zapcore.Entry{
+			Level:      zapcore.WarnLevel,
+			Time:       time.Now(),
+			LoggerName: last.entry.LoggerName,
+			Message:    "log buffer overflow",
+		}
+		if ce := last.core.Check(ent, nil); ce != nil {
+			ce.Write(zap.Int("dropped", dropped))
+		}
+	}
+}
+
+// bufferLog 将日志交给缓冲区处理，返回 true 表示已被缓冲区接管
+// 仅由 log / logMap 调用，调用链为 业务代码 -> Info -> log -> bufferLog
+func (l *zapLogger) bufferLog(ctx context.Context, b *Buffer, level Level, msg string, fields []any) bool {
+	if !b.handles(level) {
+		return false
+	}
+
+	ent := zapcore.Entry{
+		Level:      zapLevel(level),
+		Time:       time.Now(),
+		LoggerName: l.logger.Name(),
+		Message:    msg,
+	}
+	if l.opts.caller {
+		if pc, file, line, ok := runtime.Caller(3); ok {
+			ent.Caller = zapcore.NewEntryCaller(pc, file, line, true)
+		}
+	}
+
+	zapFields, _ := parseFields(fields)
+	l.bufferEntry(ctx, b, ent, zapFields)
+	return true
+}
+
+// bufferEntry 将已构造好的日志条目加入缓冲
+func (l *zapLogger) bufferEntry(ctx context.Context, b *Buffer, ent zapcore.Entry, fields []zap.Field) {
+	// 绕过 logger 级别，仍保留 With 字段、采样和各输出的级别
+	core := l.logger.Core()
+	if f, ok := core.(*levelFilterCore); ok {
+		core = f.Core
+	}
+	b.add(bufferedEntry{core: core, entry: ent, fields: l.withContextFields(ctx, fields)})
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestBufferFlushAndDiscard(t *testing.T) {
+	l, logs := newObservedLogger(t, WithLevel(WarnLevel))
+	l.opts.caller = true
+
+	ctx, buf := BeginBuffer(context.Background())
+	l.Debug(ctx, "step 1")
+	l.InfoMap(ctx, "step 2", map[string]any{"k": "v"})
+	l.Warn(ctx, "not buffered")
+	if buf.Len() != 2 || logs.Len() != 1 {
+		t.Fatalf("expected 2 buffered and 1 written, got %d and %d", buf.Len(), logs.Len())
+	}
+
+	buf.Flush()
+	entries := logs.TakeAll()
+	if len(entries) != 3 || entries[1].Message != "step 1" || entries[2].Message != "step 2" {
+		t.Fatalf("unexpected entries after flush: %+v", entries)
+	}
+	if filepath.Base(entries[1].Caller.File) != "buffer_test.go" {
+		t.Fatalf("buffered caller should point to call site, got %s", entries[1].Caller.File)
+	}
+
+	// 作用域结束后恢复正常级别过滤
+	l.Debug(ctx, "after flush")
+	if logs.Len() != 0 {
+		t.Fatal("debug log should be filtered after the buffer is closed")
+	}
+
+	ctx, buf = BeginBuffer(context.Background())
+	l.Info(ctx, "dropped")
+	buf.Discard()
+	if logs.Len() != 0 {
+		t.Fatal("discarded logs should not be written")
+	}
+}
+
+func TestBufferAutoFlushOnError(t *testing.T) {
+	l, logs := newObservedLogger(t, WithLevel(InfoLevel))
+
+	ctx, buf := BeginBuffer(context.Background(), WithBufferLimit(1))
+	l.Debug(ctx, "kept")
+	l.Debug(ctx, "overflow")
+	l.Error(ctx, "failed")
+	l.Debug(ctx, "after error")
+	buf.Discard()
+
+	var messages []string
+	for _, e := range logs.TakeAll() {
+		messages = append(messages, e.Message)
+	}
+	want := []string{"kept", "log buffer overflow", "failed", "after error"}
+	if len(messages) != len(want) {
+		t.Fatalf("unexpected messages: %v", messages)
+	}
+	for i := range want {
+		if messages[i] != want[i] {
+			t.Fatalf("unexpected messages: %v", messages)
+		}
+	}
+}
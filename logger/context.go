@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ctxFieldsKey context 中日志字段的 key
+type ctxFieldsKey struct{}
+
+// ContextWith 返回携带日志字段的 context，后续使用该 context 记录的日志都会自动包含这些字段
+// 字段以 key-value 对传入，多次调用会累加，同名字段以最后一次为准：
+//
+//	ctx = logger.ContextWith(ctx, "request_id", id, "tenant", tenant)
+//	logger.Info(ctx, "处理订单") // 自动带上 request_id 和 tenant
+func ContextWith(ctx context.Context, fields ...any) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	added := convertToZapFields(fields...)
+	if len(added) == 0 {
+		return ctx
+	}
+
+	existing, _ := ctx.Value(ctxFieldsKey{}).([]zap.Field)
+	merged := append(mergeFields(existing, added), added...)
+	return context.WithValue(ctx, ctxFieldsKey{}, merged)
+}
+
+// ContextFields 返回 context 通过 ContextWith 携带的字段（key-value 形式）
+func ContextFields(ctx context.Context) map[string]any {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(ctxFieldsKey{}).([]zap.Field)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return enc.Fields
+}
+
+// ContextExtractor 从 context 中提取日志字段，返回 key-value 对
+type ContextExtractor func(ctx context.Context) []any
+
+var (
+	extractorMu sync.Mutex
+	extractorID uint64
+	extractors  atomic.Pointer[[]registeredExtractor]
+)
+
+// registeredExtractor 已注册的提取器
+type registeredExtractor struct {
+	id uint64
+	fn ContextExtractor
+}
+
+// RegisterContextExtractor 注册 context 字段提取器，返回取消注册的函数
+// 其他模块（如 web）可以借此向日志贡献字段，而无需包装 logger；
+// 提取器在每条日志记录时调用，应保持轻量，无字段时返回 nil
+func RegisterContextExtractor(fn ContextExtractor) func() {
+	extractorMu.Lock()
+	defer extractorMu.Unlock()
+
+	extractorID++
+	id := extractorID
+
+	var list []registeredExtractor
+	if cur := extractors.Load(); cur != nil {
+		list = append(list, *cur...)
+	}
+	list = append(list, registeredExtractor{id: id, fn: fn})
+	extractors.Store(&list)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			extractorMu.Lock()
+			defer extractorMu.Unlock()
+
+			cur := extractors.Load()
+			if cur == nil {
+				return
+			}
+			list := make([]registeredExtractor, 0, len(*cur))
+			for _, e := range *cur {
+				if e.id != id {
+					list = append(list, e)
+				}
+			}
+			extractors.Store(&list)
+		})
+	}
+}
+
+// extractContextFields 提取 context 中的日志字段：先执行已注册的提取器，再追加 ContextWith 设置的字段
+func extractContextFields(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+
+	var fields []zap.Field
+	if list := extractors.Load(); list != nil {
+		for _, e := range *list {
+			if kv := e.fn(ctx); len(kv) > 0 {
+				fields = append(fields, convertToZapFields(kv...)...)
+			}
+		}
+	}
+
+	carried, _ := ctx.Value(ctxFieldsKey{}).([]zap.Field)
+	if len(fields) == 0 {
+		return carried
+	}
+	return append(mergeFields(fields, carried), carried...)
+}
+
+// mergeFields 返回 base 中未被 override 覆盖的字段
+func mergeFields(base, override []zap.Field) []zap.Field {
+	if len(base) == 0 || len(override) == 0 {
+		return base
+	}
+
+	kept := make([]zap.Field, 0, len(base)+len(override))
+	for _, f := range base {
+		if !hasField(override, f.Key) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// hasField 判断字段列表中是否存在指定 key
+func hasField(fields []zap.Field, key string) bool {
+	for _, f := range fields {
+		if f.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+type tenantKey struct{}
+
+func TestContextFields(t *testing.T) {
+	l, logs := newObservedLogger(t, WithLevel(DebugLevel))
+
+	unregister := RegisterContextExtractor(func(ctx context.Context) []any {
+		if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+			return []any{"tenant", tenant, "source", "extractor"}
+		}
+		return nil
+	})
+	defer unregister()
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	ctx = ContextWith(ctx, "request_id", "r-1", "source", "context")
+	ctx = ContextWith(ctx, "request_id", "r-2")
+
+	l.Info(ctx, "handled", "source", "call")
+	l.InfoMap(ctx, "handled map", map[string]any{"extra": 1})
+
+	entries := logs.TakeAll()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	fields := entries[0].ContextMap()
+	if fields["request_id"] != "r-2" || fields["tenant"] != "acme" || fields["source"] != "call" {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+	if n := len(entries[0].Context); n != 3 {
+		t.Fatalf("expected duplicate keys to be merged, got %d fields: %v", n, entries[0].Context)
+	}
+
+	fields = entries[1].ContextMap()
+	if fields["source"] != "context" || fields["extra"] != int64(1) {
+		t.Fatalf("unexpected map fields: %v", fields)
+	}
+
+	if got := ContextFields(ctx); got["request_id"] != "r-2" {
+		t.Fatalf("unexpected ContextFields: %v", got)
+	}
+
+	unregister()
+	l.Info(ctx, "after unregister")
+	if _, ok := logs.TakeAll()[0].ContextMap()["tenant"]; ok {
+		t.Fatal("extractor still applied after unregister")
+	}
+}
@@ -1,12 +1,35 @@
 package logger
 
 import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
 	"go.uber.org/zap/zapcore"
 )
 
 // createEncoder 创建编码器
-func createEncoder(format Format, development bool) zapcore.Encoder {
+func createEncoder(format Format, opts *options) zapcore.Encoder {
+	switch format {
+	case LogfmtFormat:
+		return newLogfmtEncoder(zapcore.EncoderConfig{
+			TimeKey:       "timestamp",
+			LevelKey:      "level",
+			NameKey:       "logger",
+			CallerKey:     "caller",
+			MessageKey:    "msg",
+			StacktraceKey: "stacktrace",
+			LineEnding:    zapcore.DefaultLineEnding,
+		})
+	case ECSFormat:
+		return newECSEncoder()
+	case GCPFormat:
+		return newGCPEncoder(opts.gcpProject)
+	}
+
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.TimeKey = "timestamp"
 	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
@@ -14,7 +37,7 @@ func createEncoder(format Format, development bool) zapcore.Encoder {
 	encoderConfig.EncodeDuration = zapcore.SecondsDurationEncoder
 	encoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
 
-	if development {
+	if opts.development {
 		encoderConfig = zap.NewDevelopmentEncoderConfig()
 		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
@@ -31,6 +54,234 @@ func createEncoder(format Format, development bool) zapcore.Encoder {
 	}
 }
 
+// ecsVersion 输出的 Elastic Common Schema 版本
+const ecsVersion = "1.6.0"
+
+// schemaEncoder 在 JSON 编码前按日志平台的 schema 调整记录，如重命名 trace 字段
+// 单次调用的字段在 EncodeEntry 中转换，With 添加的字段经 Add* 方法转换
+type schemaEncoder struct {
+	zapcore.Encoder
+	// keys 需要转换的字段名
+	keys map[string]struct{}
+	// mapField 转换 keys 中的单个字段
+	mapField func(f zapcore.Field) []zapcore.Field
+	// entryFields 返回根据日志条目生成的字段，分别置于其他字段之前和之后
+	entryFields func(ent zapcore.Entry) (head, tail []zapcore.Field)
+}
+
+// Clone 实现 zapcore.Encoder
+func (e *schemaEncoder) Clone() zapcore.Encoder {
+	c := *e
+	c.Encoder = e.Encoder.Clone()
+	return &c
+}
+
+// EncodeEntry 实现 zapcore.Encoder
+func (e *schemaEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	head, tail := e.entryFields(ent)
+	out := make([]zapcore.Field, 0, len(head)+len(fields)+len(tail)+1)
+	out = append(out, head...)
+	for _, f := range fields {
+		if e.mapped(f.Key) {
+			out = append(out, e.mapField(f)...)
+		} else {
+			out = append(out, f)
+		}
+	}
+	out = append(out, tail...)
+	return e.Encoder.EncodeEntry(ent, out)
+}
+
+// mapped 判断字段是否需要转换
+func (e *schemaEncoder) mapped(key string) bool {
+	_, ok := e.keys[key]
+	return ok
+}
+
+// add 将 With 添加的字段转换后写入内部编码器
+func (e *schemaEncoder) add(f zapcore.Field) {
+	if !e.mapped(f.Key) {
+		f.AddTo(e.Encoder)
+		return
+	}
+	for _, m := range e.mapField(f) {
+		m.AddTo(e.Encoder)
+	}
+}
+
+// 以下方法实现 zapcore.ObjectEncoder，使 With 添加的字段与单次调用的字段转换一致
+
+func (e *schemaEncoder) AddArray(key string, v zapcore.ArrayMarshaler) error {
+	if !e.mapped(key) {
+		return e.Encoder.AddArray(key, v)
+	}
+	e.add(zap.Array(key, v))
+	return nil
+}
+
+func (e *schemaEncoder) AddObject(key string, v zapcore.ObjectMarshaler) error {
+	if !e.mapped(key) {
+		return e.Encoder.AddObject(key, v)
+	}
+	e.add(zap.Object(key, v))
+	return nil
+}
+
+func (e *schemaEncoder) AddReflected(key string, v interface{}) error {
+	if !e.mapped(key) {
+		return e.Encoder.AddReflected(key, v)
+	}
+	e.add(zap.Reflect(key, v))
+	return nil
+}
+
+func (e *schemaEncoder) AddBinary(key string, v []byte)          { e.add(zap.Binary(key, v)) }
+func (e *schemaEncoder) AddByteString(key string, v []byte)      { e.add(zap.ByteString(key, v)) }
+func (e *schemaEncoder) AddBool(key string, v bool)              { e.add(zap.Bool(key, v)) }
+func (e *schemaEncoder) AddComplex128(key string, v complex128)  { e.add(zap.Complex128(key, v)) }
+func (e *schemaEncoder) AddComplex64(key string, v complex64)    { e.add(zap.Complex64(key, v)) }
+func (e *schemaEncoder) AddDuration(key string, v time.Duration) { e.add(zap.Duration(key, v)) }
+func (e *schemaEncoder) AddFloat64(key string, v float64)        { e.add(zap.Float64(key, v)) }
+func (e *schemaEncoder) AddFloat32(key string, v float32)        { e.add(zap.Float32(key, v)) }
+func (e *schemaEncoder) AddInt(key string, v int)                { e.add(zap.Int(key, v)) }
+func (e *schemaEncoder) AddInt64(key string, v int64)            { e.add(zap.Int64(key, v)) }
+func (e *schemaEncoder) AddInt32(key string, v int32)            { e.add(zap.Int32(key, v)) }
+func (e *schemaEncoder) AddInt16(key string, v int16)            { e.add(zap.Int16(key, v)) }
+func (e *schemaEncoder) AddInt8(key string, v int8)              { e.add(zap.Int8(key, v)) }
+func (e *schemaEncoder) AddString(key, v string)                 { e.add(zap.String(key, v)) }
+func (e *schemaEncoder) AddTime(key string, v time.Time)         { e.add(zap.Time(key, v)) }
+func (e *schemaEncoder) AddUint(key string, v uint)              { e.add(zap.Uint(key, v)) }
+func (e *schemaEncoder) AddUint64(key string, v uint64)          { e.add(zap.Uint64(key, v)) }
+func (e *schemaEncoder) AddUint32(key string, v uint32)          { e.add(zap.Uint32(key, v)) }
+func (e *schemaEncoder) AddUint16(key string, v uint16)          { e.add(zap.Uint16(key, v)) }
+func (e *schemaEncoder) AddUint8(key string, v uint8)            { e.add(zap.Uint8(key, v)) }
+func (e *schemaEncoder) AddUintptr(key string, v uintptr)        { e.add(zap.Uintptr(key, v)) }
+
+// newECSEncoder 创建 Elastic Common Schema JSON 编码器
+// trace_id / span_id 映射为 trace.id / span.id，caller 映射为 log.origin
+func newECSEncoder() zapcore.Encoder {
+	cfg := zapcore.EncoderConfig{
+		TimeKey:        "@timestamp",
+		LevelKey:       "log.level",
+		NameKey:        "log.logger",
+		MessageKey:     "message",
+		StacktraceKey:  "error.stack_trace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeName:     zapcore.FullNameEncoder,
+	}
+
+	renames := map[string]string{
+		"trace_id": "trace.id",
+		"span_id":  "span.id",
+		"error":    "error.message",
+	}
+	keys := make(map[string]struct{}, len(renames))
+	for key := range renames {
+		keys[key] = struct{}{}
+	}
+	return &schemaEncoder{
+		Encoder: zapcore.NewJSONEncoder(cfg),
+		keys:    keys,
+		mapField: func(f zapcore.Field) []zapcore.Field {
+			f.Key = renames[f.Key]
+			return []zapcore.Field{f}
+		},
+		entryFields: func(ent zapcore.Entry) (head, tail []zapcore.Field) {
+			head = []zapcore.Field{zap.String("ecs.version", ecsVersion)}
+			if ent.Caller.Defined {
+				tail = append(tail, zap.Object("log.origin", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+					enc.AddString("file.name", strings.TrimSuffix(ent.Caller.TrimmedPath(), ":"+strconv.Itoa(ent.Caller.Line)))
+					enc.AddInt("file.line", ent.Caller.Line)
+					if ent.Caller.Function != "" {
+						enc.AddString("function", ent.Caller.Function)
+					}
+					return nil
+				})))
+			}
+			return head, tail
+		},
+	}
+}
+
+// newGCPEncoder 创建 Google Cloud Logging 结构化 JSON 编码器
+// trace_id / span_id 映射为 logging.googleapis.com/trace 和 spanId，caller 映射为 sourceLocation
+// project 为空时使用环境变量 GOOGLE_CLOUD_PROJECT，仍为空时 trace 字段只包含 trace id
+func newGCPEncoder(project string) zapcore.Encoder {
+	if project == "" {
+		project = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+
+	cfg := zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LevelKey:       "severity",
+		NameKey:        "logger",
+		MessageKey:     "message",
+		StacktraceKey:  "stack_trace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeLevel:    gcpSeverityEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeName:     zapcore.FullNameEncoder,
+	}
+
+	return &schemaEncoder{
+		Encoder: zapcore.NewJSONEncoder(cfg),
+		keys:    map[string]struct{}{"trace_id": {}, "span_id": {}},
+		mapField: func(f zapcore.Field) []zapcore.Field {
+			switch {
+			case f.Key == "trace_id" && f.Type == zapcore.StringType:
+				trace := f.String
+				if project != "" {
+					trace = "projects/" + project + "/traces/" + f.String
+				}
+				return []zapcore.Field{zap.String("logging.googleapis.com/trace", trace)}
+			case f.Key == "span_id" && f.Type == zapcore.StringType:
+				return []zapcore.Field{zap.String("logging.googleapis.com/spanId", f.String)}
+			default:
+				return []zapcore.Field{f}
+			}
+		},
+		entryFields: func(ent zapcore.Entry) (head, tail []zapcore.Field) {
+			if ent.Caller.Defined {
+				tail = append(tail, zap.Object("logging.googleapis.com/sourceLocation", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+					enc.AddString("file", ent.Caller.File)
+					enc.AddString("line", strconv.Itoa(ent.Caller.Line))
+					if ent.Caller.Function != "" {
+						enc.AddString("function", ent.Caller.Function)
+					}
+					return nil
+				})))
+			}
+			return nil, tail
+		},
+	}
+}
+
+// gcpSeverityEncoder 将日志级别编码为 Cloud Logging 的 severity
+func gcpSeverityEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	switch level {
+	case zapcore.DebugLevel:
+		enc.AppendString("DEBUG")
+	case zapcore.InfoLevel:
+		enc.AppendString("INFO")
+	case zapcore.WarnLevel:
+		enc.AppendString("WARNING")
+	case zapcore.ErrorLevel:
+		enc.AppendString("ERROR")
+	case zapcore.DPanicLevel:
+		enc.AppendString("CRITICAL")
+	case zapcore.PanicLevel:
+		enc.AppendString("ALERT")
+	case zapcore.FatalLevel:
+		enc.AppendString("EMERGENCY")
+	default:
+		enc.AppendString("DEFAULT")
+	}
+}
+
 // zapLevel 将自定义日志级别转换为 zap 日志级别
 func zapLevel(level Level) zapcore.Level {
 	switch level {
@@ -49,3 +300,18 @@ func zapLevel(level Level) zapcore.Level {
 	}
 }
 
+// fromZapLevel 将 zap 日志级别转换为自定义日志级别
+func fromZapLevel(level zapcore.Level) Level {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return DebugLevel
+	case level == zapcore.InfoLevel:
+		return InfoLevel
+	case level == zapcore.WarnLevel:
+		return WarnLevel
+	case level == zapcore.ErrorLevel:
+		return ErrorLevel
+	default:
+		return FatalLevel
+	}
+}
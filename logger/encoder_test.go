@@ -0,0 +1,213 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	testTraceID = "0102030405060708090a0b0c0d0e0f10"
+	testSpanID  = "0102030405060708"
+)
+
+// logToFile 使用指定格式写入一条带 trace 字段的日志，返回文件内容
+func logToFile(t *testing.T, format Format, opts ...Option) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.log")
+	opts = append([]Option{
+		WithStdout(WithOutputLevel(FatalLevel)),
+		WithFile(path, WithOutputFormat(format)),
+	}, opts...)
+	l, err := New(opts...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	l.Named("orders").Error(context.Background(), "payment failed",
+		"trace_id", testTraceID, "span_id", testSpanID,
+		"order id", "A 1", "amount", 12.5, errors.New("card declined"))
+	_ = l.Sync()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return string(data)
+}
+
+func TestLogfmtFormat(t *testing.T) {
+	line := strings.TrimSpace(logToFile(t, LogfmtFormat))
+
+	for _, want := range []string{
+		"level=error",
+		"logger=orders",
+		`msg="payment failed"`,
+		"trace_id=" + testTraceID,
+		`order_id="A 1"`,
+		"amount=12.5",
+		`error="card declined"`,
+		"caller=logger/encoder_test.go:",
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("logfmt line missing %q: %s", want, line)
+		}
+	}
+	if !strings.HasPrefix(line, "timestamp=") {
+		t.Errorf("logfmt line should start with timestamp: %s", line)
+	}
+}
+
+func TestLogfmtEncoderNestedAndWith(t *testing.T) {
+	enc := newLogfmtEncoder(zapcore.EncoderConfig{MessageKey: "msg", LineEnding: "\n"})
+	zap.String("service", "api").AddTo(enc)
+	clone := enc.Clone()
+
+	buf, err := clone.EncodeEntry(zapcore.Entry{Message: "hi", Time: time.Now()}, []zapcore.Field{
+		zap.Object("user", zapcore.ObjectMarshalerFunc(func(o zapcore.ObjectEncoder) error {
+			o.AddString("id", "u1")
+			o.AddInt("age", 30)
+			return nil
+		})),
+		zap.Strings("tags", []string{"a", "b"}),
+		zap.Duration("took", 1500*time.Millisecond),
+	})
+	if err != nil {
+		t.Fatalf("EncodeEntry: %v", err)
+	}
+	want := `msg=hi service=api user.id=u1 user.age=30 tags="[\"a\",\"b\"]" took=1.5s` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestLogfmtEncoderDoesNotShareNamespaces(t *testing.T) {
+	enc := newLogfmtEncoder(zapcore.EncoderConfig{MessageKey: "msg", LineEnding: "\n"})
+	// 模拟 With(zap.Namespace(...)) 后命名空间切片还有剩余容量
+	enc.namespaces = append(make([]string, 0, 4), "req")
+
+	for _, ns := range []string{"a", "b"} {
+		buf, err := enc.EncodeEntry(zapcore.Entry{Message: "hi"}, []zapcore.Field{
+			zap.Namespace(ns), zap.Int("n", 1),
+		})
+		if err != nil {
+			t.Fatalf("EncodeEntry: %v", err)
+		}
+		if got, want := buf.String(), "msg=hi req."+ns+".n=1\n"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+
+	// 并发的 EncodeEntry 共享 enc.namespaces，字段中的命名空间不能写入其剩余容量
+	if spare := enc.namespaces[:cap(enc.namespaces)][1]; spare != "" {
+		t.Errorf("EncodeEntry wrote %q into the shared namespace slice", spare)
+	}
+}
+
+func TestECSFormat(t *testing.T) {
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(logToFile(t, ECSFormat)), &entry); err != nil {
+		t.Fatalf("ECS output is not JSON: %v", err)
+	}
+
+	for key, want := range map[string]any{
+		"log.level":     "error",
+		"log.logger":    "orders",
+		"message":       "payment failed",
+		"trace.id":      testTraceID,
+		"span.id":       testSpanID,
+		"error.message": "card declined",
+		"ecs.version":   ecsVersion,
+	} {
+		if entry[key] != want {
+			t.Errorf("%s = %v, want %v", key, entry[key], want)
+		}
+	}
+	if _, ok := entry["@timestamp"]; !ok {
+		t.Error("@timestamp missing")
+	}
+	origin, _ := entry["log.origin"].(map[string]any)
+	if file, _ := origin["file.name"].(string); !strings.HasSuffix(file, "encoder_test.go") {
+		t.Errorf("log.origin = %v", entry["log.origin"])
+	}
+}
+
+func TestGCPFormat(t *testing.T) {
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(logToFile(t, GCPFormat, WithGCPProject("my-project"))), &entry); err != nil {
+		t.Fatalf("GCP output is not JSON: %v", err)
+	}
+
+	for key, want := range map[string]any{
+		"severity":                      "ERROR",
+		"message":                       "payment failed",
+		"logger":                        "orders",
+		"logging.googleapis.com/trace":  "projects/my-project/traces/" + testTraceID,
+		"logging.googleapis.com/spanId": testSpanID,
+	} {
+		if entry[key] != want {
+			t.Errorf("%s = %v, want %v", key, entry[key], want)
+		}
+	}
+	loc, _ := entry["logging.googleapis.com/sourceLocation"].(map[string]any)
+	if file, _ := loc["file"].(string); !strings.HasSuffix(file, "encoder_test.go") || loc["line"] == "" {
+		t.Errorf("sourceLocation = %v", entry["logging.googleapis.com/sourceLocation"])
+	}
+	if _, ok := entry["trace_id"]; ok {
+		t.Error("trace_id should be mapped to logging.googleapis.com/trace")
+	}
+	if _, ok := entry["logging.googleapis.com/trace_sampled"]; ok {
+		t.Error("trace_sampled should not be reported without the sampling decision")
+	}
+}
+
+func TestSchemaFormatsWithFields(t *testing.T) {
+	for _, tt := range []struct {
+		format Format
+		want   map[string]any
+		absent []string
+	}{
+		{ECSFormat, map[string]any{
+			"error.message": "card declined",
+			"trace.id":      testTraceID,
+			"span.id":       testSpanID,
+			"order_id":      "A1",
+		}, []string{"error", "trace_id", "span_id"}},
+		{GCPFormat, map[string]any{
+			"logging.googleapis.com/trace":  "projects/my-project/traces/" + testTraceID,
+			"logging.googleapis.com/spanId": testSpanID,
+			"error":                         "card declined",
+		}, []string{"trace_id", "span_id"}},
+	} {
+		path := filepath.Join(t.TempDir(), "app.log")
+		l, err := New(WithStdout(WithOutputLevel(FatalLevel)), WithFile(path, WithOutputFormat(tt.format)), WithGCPProject("my-project"))
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		l.With(errors.New("card declined"), "trace_id", testTraceID, "span_id", testSpanID, "order_id", "A1").
+			Error(context.Background(), "payment failed")
+		_ = l.Sync()
+
+		lines := readJSONLines(t, path)
+		if len(lines) != 1 {
+			t.Fatalf("%s: got %d lines", tt.format, len(lines))
+		}
+		for key, want := range tt.want {
+			if lines[0][key] != want {
+				t.Errorf("%s: %s = %v, want %v", tt.format, key, lines[0][key], want)
+			}
+		}
+		for _, key := range tt.absent {
+			if _, ok := lines[0][key]; ok {
+				t.Errorf("%s: %s should be renamed", tt.format, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultExitTimeout Fatal 退出前执行退出回调和刷新输出各自的最长等待时间
+const defaultExitTimeout = 5 * time.Second
+
+// osExit 退出进程，测试中替换
+var osExit = os.Exit
+
+var (
+	exitMu       sync.Mutex
+	exitHandlers []func()
+)
+
+// RegisterExitHandler 注册退出回调，Fatal 日志（或 Exit）在刷新日志输出和退出进程之前按注册顺序调用
+// 回调中可以继续记录日志，适合关闭数据库连接、上报状态等；回调中的 panic 会被恢复
+func RegisterExitHandler(fn func()) {
+	if fn == nil {
+		return
+	}
+	exitMu.Lock()
+	exitHandlers = append(exitHandlers, fn)
+	exitMu.Unlock()
+}
+
+// runExitHandlers 依次调用退出回调，最多等待 timeout
+func runExitHandlers(timeout time.Duration) {
+	exitMu.Lock()
+	handlers := append([]func(){}, exitHandlers...)
+	exitMu.Unlock()
+	if len(handlers) == 0 {
+		return
+	}
+
+	waitTimeout("exit handlers", timeout, func() {
+		for _, fn := range handlers {
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						internalLogf("exit handler panicked: %v", r)
+					}
+				}()
+				fn()
+			}()
+		}
+	})
+}
+
+// Exit 调用退出回调，刷新默认 logger 的全部输出后以 code 退出进程
+// 用于替代 os.Exit，避免 OTLP 批次等尚未发送的日志丢失
+func Exit(code int) {
+	timeout := defaultExitTimeout
+	var flush func()
+	if l, ok := Default().(*zapLogger); ok && l.fatal != nil {
+		timeout = l.fatal.timeout
+		flush = l.fatal.flush
+	} else {
+		flush = func() { _ = Default().Sync() }
+	}
+
+	runExitHandlers(timeout)
+	waitTimeout("flush", timeout, flush)
+	osExit(code)
+}
+
+// FatalPanic 启用 WithFatalPanic 时 Fatal 日志引发的 panic 值
+type FatalPanic struct {
+	Message string
+}
+
+func (p FatalPanic) Error() string { return "logger: fatal: " + p.Message }
+
+// fatalHook 替代 zap 默认的 Fatal 处理：调用退出回调、刷新全部输出后再退出
+type fatalHook struct {
+	core     zapcore.Core // 组合后的 core，刷新全部输出和 hook
+	drops    *dropCounter
+	otlp     []*otlpSyncer
+	rotators []*fileRotator
+	timeout  time.Duration
+	panics   bool // 为 true 时刷新后 panic，不调用退出回调也不退出进程
+}
+
+// OnWrite 实现 zapcore.CheckWriteHook，在 Fatal 日志写入全部输出后调用
+func (h *fatalHook) OnWrite(ce *zapcore.CheckedEntry, _ []zapcore.Field) {
+	h.exit(ce.Message)
+}
+
+// exit 调用退出回调、刷新全部输出后退出进程，panic 模式下刷新后 panic
+func (h *fatalHook) exit(msg string) {
+	if h.panics {
+		waitTimeout("flush", h.timeout, h.sync)
+		panic(FatalPanic{Message: msg})
+	}
+
+	runExitHandlers(h.timeout)
+	waitTimeout("flush", h.timeout, h.flush)
+	osExit(1)
+}
+
+// sync 输出丢弃统计并刷新全部输出
+func (h *fatalHook) sync() {
+	h.drops.flush()
+	_ = h.core.Sync()
+}
+
+// flush 刷新全部输出并关闭 OTLP 导出器和文件切割器，发送队列和重试中的批次，完成待压缩的文件
+func (h *fatalHook) flush() {
+	h.sync()
+	var wg sync.WaitGroup
+	for _, s := range h.otlp {
+		wg.Add(1)
+		go func(s *otlpSyncer) {
+			defer wg.Done()
+			_ = s.Close()
+		}(s)
+	}
+	for _, r := range h.rotators {
+		wg.Add(1)
+		go func(r *fileRotator) {
+			defer wg.Done()
+			_ = r.Close()
+		}(r)
+	}
+	wg.Wait()
+}
+
+// waitTimeout 执行 fn，最多等待 timeout，超时时输出到 stderr
+func waitTimeout(name string, timeout time.Duration, fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		internalLogf("%s before exit timed out after %v", name, timeout)
+	}
+}
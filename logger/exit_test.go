@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// stubExit 替换 osExit 并清空退出回调，测试结束时恢复
+func stubExit(t *testing.T) *int {
+	t.Helper()
+	code := -1
+	oldExit := osExit
+	osExit = func(c int) { code = c }
+
+	exitMu.Lock()
+	oldHandlers := exitHandlers
+	exitHandlers = nil
+	exitMu.Unlock()
+
+	t.Cleanup(func() {
+		osExit = oldExit
+		exitMu.Lock()
+		exitHandlers = oldHandlers
+		exitMu.Unlock()
+	})
+	return &code
+}
+
+func TestFatalFlushesOutputsBeforeExit(t *testing.T) {
+	code := stubExit(t)
+	addr := freeAddr(t)
+	collector := startCollector(t, addr)
+
+	var hooked hookRecorder
+	l, err := New(
+		WithStdout(WithOutputLevel(FatalLevel)),
+		// 批次间隔很长，只有退出前的刷新会发送
+		WithOTLP(addr, WithOTLPInsecure(), WithOTLPBatch(100, time.Hour)),
+		WithHook(hooked.hook, WithHookLevel(FatalLevel)),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var order []string
+	RegisterExitHandler(func() {
+		order = append(order, "first")
+		l.Info(context.Background(), "closing resources")
+	})
+	RegisterExitHandler(func() { panic("broken handler") })
+	RegisterExitHandler(func() { order = append(order, "third") })
+
+	l.Info(context.Background(), "queued")
+	l.Fatal(context.Background(), "fatal error")
+
+	if *code != 1 {
+		t.Fatalf("exit code = %d, want 1", *code)
+	}
+	if !slices.Equal(order, []string{"first", "third"}) {
+		t.Errorf("exit handlers ran %v", order)
+	}
+	records, _ := collector.snapshot()
+	for _, msg := range []string{"queued", "fatal error", "closing resources"} {
+		if !slices.Contains(records, msg) {
+			t.Errorf("record %q not exported before exit, got %v", msg, records)
+		}
+	}
+	if got := hooked.all(); len(got) != 1 || got[0].Message != "fatal error" {
+		t.Errorf("hook entries = %+v", got)
+	}
+}
+
+func TestWithFatalPanic(t *testing.T) {
+	code := stubExit(t)
+	called := false
+	RegisterExitHandler(func() { called = true })
+
+	var hooked hookRecorder
+	l, err := New(
+		WithStdout(WithOutputLevel(FatalLevel)),
+		WithFatalPanic(),
+		WithHook(hooked.hook, WithHookLevel(FatalLevel)),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	func() {
+		defer func() {
+			p, ok := recover().(FatalPanic)
+			if !ok || p.Message != "boom" {
+				t.Errorf("recovered %#v, want FatalPanic", p)
+			}
+		}()
+		l.Named("worker").FatalMap(context.Background(), "boom", map[string]any{"id": 1})
+		t.Error("Fatal should not return")
+	}()
+
+	if *code != -1 || called {
+		t.Errorf("panic mode should not exit or run exit handlers (code %d, handler called %v)", *code, called)
+	}
+	if got := hooked.all(); len(got) != 1 {
+		t.Errorf("hook should be flushed before panic, got %d entries", len(got))
+	}
+}
+
+func TestExit(t *testing.T) {
+	code := stubExit(t)
+	old := Default()
+	t.Cleanup(func() { SetDefault(old) })
+
+	var hooked hookRecorder
+	l, err := New(WithStdout(WithOutputLevel(FatalLevel)), WithHook(hooked.hook))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	SetDefault(l)
+
+	called := false
+	RegisterExitHandler(func() { called = true })
+	Error(context.Background(), "shutting down")
+	Exit(3)
+
+	if *code != 3 || !called {
+		t.Errorf("exit code %d, handler called %v", *code, called)
+	}
+	if len(hooked.all()) != 1 {
+		t.Error("Exit should flush hooks")
+	}
+}
@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// badKey 无法解析为 key-value 对的字段使用的 key，与 log/slog 保持一致
+const badKey = "!BADKEY"
+
+// Field 强类型日志字段，可以与 key-value 对混合传入日志方法：
+//
+//	logger.Info(ctx, "订单创建", logger.String("order_id", id), logger.Int("items", n), "source", "api")
+//
+// 通过构造函数创建的字段不需要反射。传入 Info 等方法时字段会装箱为 any，每个字段产生一次内存分配；
+// 热点路径使用 InfoFields 等方法，字段不经过装箱：
+//
+//	logger.InfoFields(ctx, "订单创建", logger.String("order_id", id), logger.Int("items", n))
+type Field struct {
+	field zap.Field
+}
+
+// String 字符串字段
+func String(key, value string) Field {
+	return Field{field: zap.String(key, value)}
+}
+
+// Int 整数字段
+func Int(key string, value int) Field {
+	return Field{field: zap.Int(key, value)}
+}
+
+// Int64 64 位整数字段
+func Int64(key string, value int64) Field {
+	return Field{field: zap.Int64(key, value)}
+}
+
+// Float64 浮点数字段
+func Float64(key string, value float64) Field {
+	return Field{field: zap.Float64(key, value)}
+}
+
+// Bool 布尔字段
+func Bool(key string, value bool) Field {
+	return Field{field: zap.Bool(key, value)}
+}
+
+// Duration 时长字段
+func Duration(key string, value time.Duration) Field {
+	return Field{field: zap.Duration(key, value)}
+}
+
+// Time 时间字段
+func Time(key string, value time.Time) Field {
+	return Field{field: zap.Time(key, value)}
+}
+
+// Err 错误字段，key 固定为 error；err 为 nil 时不输出
+func Err(err error) Field {
+	return Field{field: zap.Error(err)}
+}
+
+// Any 任意类型字段，常见类型会自动选择对应的编码方式，其余类型使用反射
+func Any(key string, value any) Field {
+	return Field{field: zap.Any(key, value)}
+}
+
+// Group 将多个字段组合为嵌套对象
+func Group(key string, fields ...Field) Field {
+	return Field{field: zap.Object(key, fieldGroup(fields))}
+}
+
+// asZapFields 将 []Field 转换为 []zap.Field，只分配一次切片，分配次数与字段数量无关
+func asZapFields(fields []Field) []zap.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+	zf := make([]zap.Field, len(fields))
+	for i, f := range fields {
+		zf[i] = f.field
+	}
+	return zf
+}
+
+// Key 返回字段名
+func (f Field) Key() string {
+	return f.field.Key
+}
+
+// fieldGroup 将一组字段编码为 zap 对象
+type fieldGroup []Field
+
+// MarshalLogObject 实现 zapcore.ObjectMarshaler
+func (g fieldGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range g {
+		f.field.AddTo(enc)
+	}
+	return nil
+}
+
+// convertToZapFields 转换字段为 zap.Field，忽略格式问题
+func convertToZapFields(fields ...any) []zap.Field {
+	zapFields, _ := parseFields(fields)
+	return zapFields
+}
+
+// parseFields 将日志方法的可变参数转换为 zap.Field，并返回格式问题的描述
+//
+// 支持的写法：
+//   - Field / []Field：强类型字段
+//   - "key", value：key-value 对
+//   - map[string]any：按 key 排序展开
+//   - error：等价于 Err(err)
+//
+// 无法解析的参数以 !BADKEY 记录，不会丢失内容。
+func parseFields(fields []any) ([]zap.Field, []string) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	zapFields := make([]zap.Field, 0, len(fields))
+	var problems []string
+
+	for i := 0; i < len(fields); i++ {
+		switch v := fields[i].(type) {
+		case Field:
+			zapFields = append(zapFields, v.field)
+		case []Field:
+			for _, f := range v {
+				zapFields = append(zapFields, f.field)
+			}
+		case string:
+			if i+1 >= len(fields) {
+				zapFields = append(zapFields, zap.String(badKey, v))
+				problems = append(problems, fmt.Sprintf("missing value for key %q", v))
+				continue
+			}
+			zapFields = append(zapFields, zap.Any(v, fields[i+1]))
+			i++
+		case map[string]any:
+			zapFields = appendMapFields(zapFields, v)
+		case error:
+			zapFields = append(zapFields, zap.Error(v))
+		default:
+			zapFields = append(zapFields, zap.Any(badKey, v))
+			problems = append(problems, fmt.Sprintf("non-string key %v (%T) at position %d", v, v, i))
+		}
+	}
+
+	return zapFields, problems
+}
+
+// appendMapFields 按 key 排序追加 map 字段，保证输出顺序稳定
+func appendMapFields(dst []zap.Field, m map[string]any) []zap.Field {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		dst = append(dst, zap.Any(k, m[k]))
+	}
+	return dst
+}
@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestTypedFields(t *testing.T) {
+	l, logs := newObservedLogger(t, WithLevel(DebugLevel))
+	ctx := context.Background()
+
+	l.Info(ctx, "typed",
+		String("s", "v"),
+		Int("i", 1),
+		Duration("d", time.Second),
+		Err(errors.New("boom")),
+		Group("g", Bool("b", true), Float64("f", 1.5)),
+		"k", "loose",
+	)
+	l.Info(ctx, "map value", map[string]any{"b": 2, "a": 1})
+
+	entries := logs.TakeAll()
+	fields := entries[0].ContextMap()
+	if fields["s"] != "v" || fields["i"] != int64(1) || fields["d"] != time.Second || fields["error"] != "boom" || fields["k"] != "loose" {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+	if g, ok := fields["g"].(map[string]any); !ok || g["b"] != true || g["f"] != 1.5 {
+		t.Fatalf("unexpected group: %v", fields["g"])
+	}
+
+	ctxFields := entries[1].Context
+	if len(ctxFields) != 2 || ctxFields[0].Key != "a" || ctxFields[1].Key != "b" {
+		t.Fatalf("map should be expanded in key order: %v", ctxFields)
+	}
+}
+
+func TestMalformedFields(t *testing.T) {
+	l, logs := newObservedLogger(t, WithLevel(DebugLevel))
+	ctx := context.Background()
+
+	l.Info(ctx, "lenient", 42, "x", "v", "dangling")
+	entries := logs.TakeAll()
+	if len(entries) != 1 {
+		t.Fatalf("expected no report without strict mode, got %d entries", len(entries))
+	}
+	fields := entries[0].Context
+	if len(fields) != 3 || fields[0].Key != badKey || fields[2].Key != badKey {
+		t.Fatalf("malformed pairs should be kept under %s: %v", badKey, fields)
+	}
+
+	strict, logs := newObservedLogger(t, WithLevel(DebugLevel), WithStrictFields(true))
+	strict.Info(ctx, "strict", "dangling")
+	entries = logs.TakeAll()
+	if len(entries) != 2 {
+		t.Fatalf("expected report and original entry, got %d", len(entries))
+	}
+	report := entries[0]
+	if report.Message != "malformed log fields" || report.ContextMap()["log_message"] != "strict" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if filepath.Base(report.Caller.File) != "field_test.go" {
+		t.Fatalf("report should point to the call site, got %s", report.Caller.File)
+	}
+}
+
+func TestFieldsMethods(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l, err := New(WithLevel(DebugLevel), WithCore(core))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx := ContextWith(context.Background(), "request_id", "r-1")
+
+	fields := []Field{String("s", "v"), Int("i", 1)}
+	l.InfoFields(ctx, "typed", fields...)
+	l.Named("orders").WarnFields(ctx, "module", Err(errors.New("boom")))
+	l.DebugFields(ctx, "no fields")
+
+	entries := logs.TakeAll()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries", len(entries))
+	}
+	got := entries[0].ContextMap()
+	if got["s"] != "v" || got["i"] != int64(1) || got["request_id"] != "r-1" {
+		t.Errorf("fields = %v", got)
+	}
+	if !strings.HasSuffix(entries[0].Caller.File, "field_test.go") {
+		t.Errorf("caller = %s", entries[0].Caller.TrimmedPath())
+	}
+	if entries[1].LoggerName != "orders" || entries[1].Level != zapcore.WarnLevel || entries[1].ContextMap()["error"] != "boom" {
+		t.Errorf("entry = %+v", entries[1])
+	}
+	if fields[0].Key() != "s" || fields[1].Key() != "i" {
+		t.Errorf("caller's fields were modified: %v", fields)
+	}
+}
+
+// newDiscardLogger 创建编码为 JSON 后丢弃的 logger，用于统计内存分配
+func newDiscardLogger(tb testing.TB) Logger {
+	tb.Helper()
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(io.Discard), zapcore.DebugLevel)
+	l, err := New(WithLevel(DebugLevel), WithCore(core))
+	if err != nil {
+		tb.Fatalf("New: %v", err)
+	}
+	return l
+}
+
+func TestFieldsAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not stable under the race detector")
+	}
+	if testing.Short() {
+		t.Skip("skipping allocation test in short mode")
+	}
+	l := newDiscardLogger(t)
+	ctx := context.Background()
+
+	one := testing.AllocsPerRun(100, func() {
+		l.InfoFields(ctx, "m", String("a", "b"))
+	})
+	six := testing.AllocsPerRun(100, func() {
+		l.InfoFields(ctx, "m", String("a", "b"), Int("n", 1), Bool("ok", true),
+			Int64("id", 2), Float64("f", 1.5), Duration("d", time.Second))
+	})
+	boxed := testing.AllocsPerRun(100, func() {
+		l.Info(ctx, "m", String("a", "b"), Int("n", 1), Bool("ok", true),
+			Int64("id", 2), Float64("f", 1.5), Duration("d", time.Second))
+	})
+
+	// InfoFields 的内存分配次数与字段数量无关，Info 中每个字段装箱为 any 各分配一次
+	if six > one {
+		t.Errorf("InfoFields allocs grew with field count: 1 field = %v, 6 fields = %v", one, six)
+	}
+	if six >= boxed {
+		t.Errorf("InfoFields allocs = %v, want fewer than Info with boxed fields (%v)", six, boxed)
+	}
+}
+
+func BenchmarkInfoKeyValues(b *testing.B) {
+	l := newDiscardLogger(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info(ctx, "order created", "order_id", "o-1", "items", 3, "paid", true)
+	}
+}
+
+func BenchmarkInfoTypedFields(b *testing.B) {
+	l := newDiscardLogger(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info(ctx, "order created", String("order_id", "o-1"), Int("items", 3), Bool("paid", true))
+	}
+}
+
+func BenchmarkInfoFields(b *testing.B) {
+	l := newDiscardLogger(b)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.InfoFields(ctx, "order created", String("order_id", "o-1"), Int("items", 3), Bool("paid", true))
+	}
+}
@@ -50,11 +50,22 @@ func SetDefault(l Logger) {
 
 // 包级便捷函数 - 使用默认 logger
 
+// directLogger 可以按级别直接记录日志的 logger 实现
+// 包级函数直接调用 log，使调用深度与 logger.Info 等方法一致，caller 指向业务代码
+type directLogger interface {
+	log(ctx context.Context, level Level, msg string, fields ...any)
+	logFields(ctx context.Context, level Level, msg string, fields []Field)
+}
+
 // Debug 记录 debug 级别日志
 func Debug(ctx context.Context, msg string, fields ...any) {
 	mu.RLock()
 	l := defaultLogger
 	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.log(ctx, DebugLevel, msg, fields...)
+		return
+	}
 	l.Debug(ctx, msg, fields...)
 }
 
@@ -63,6 +74,10 @@ func Info(ctx context.Context, msg string, fields ...any) {
 	mu.RLock()
 	l := defaultLogger
 	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.log(ctx, InfoLevel, msg, fields...)
+		return
+	}
 	l.Info(ctx, msg, fields...)
 }
 
@@ -71,6 +86,10 @@ func Warn(ctx context.Context, msg string, fields ...any) {
 	mu.RLock()
 	l := defaultLogger
 	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.log(ctx, WarnLevel, msg, fields...)
+		return
+	}
 	l.Warn(ctx, msg, fields...)
 }
 
@@ -79,6 +98,10 @@ func Error(ctx context.Context, msg string, fields ...any) {
 	mu.RLock()
 	l := defaultLogger
 	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.log(ctx, ErrorLevel, msg, fields...)
+		return
+	}
 	l.Error(ctx, msg, fields...)
 }
 
@@ -87,6 +110,10 @@ func Fatal(ctx context.Context, msg string, fields ...any) {
 	mu.RLock()
 	l := defaultLogger
 	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.log(ctx, FatalLevel, msg, fields...)
+		return
+	}
 	l.Fatal(ctx, msg, fields...)
 }
 
@@ -95,6 +122,10 @@ func DebugMap(ctx context.Context, msg string, fields map[string]any) {
 	mu.RLock()
 	l := defaultLogger
 	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.log(ctx, DebugLevel, msg, fields)
+		return
+	}
 	l.DebugMap(ctx, msg, fields)
 }
 
@@ -103,6 +134,10 @@ func InfoMap(ctx context.Context, msg string, fields map[string]any) {
 	mu.RLock()
 	l := defaultLogger
 	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.log(ctx, InfoLevel, msg, fields)
+		return
+	}
 	l.InfoMap(ctx, msg, fields)
 }
 
@@ -111,6 +146,10 @@ func WarnMap(ctx context.Context, msg string, fields map[string]any) {
 	mu.RLock()
 	l := defaultLogger
 	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.log(ctx, WarnLevel, msg, fields)
+		return
+	}
 	l.WarnMap(ctx, msg, fields)
 }
 
@@ -119,6 +158,10 @@ func ErrorMap(ctx context.Context, msg string, fields map[string]any) {
 	mu.RLock()
 	l := defaultLogger
 	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.log(ctx, ErrorLevel, msg, fields)
+		return
+	}
 	l.ErrorMap(ctx, msg, fields)
 }
 
@@ -127,9 +170,73 @@ func FatalMap(ctx context.Context, msg string, fields map[string]any) {
 	mu.RLock()
 	l := defaultLogger
 	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.log(ctx, FatalLevel, msg, fields)
+		return
+	}
 	l.FatalMap(ctx, msg, fields)
 }
 
+// DebugFields 记录 debug 级别日志（强类型字段）
+func DebugFields(ctx context.Context, msg string, fields ...Field) {
+	mu.RLock()
+	l := defaultLogger
+	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.logFields(ctx, DebugLevel, msg, fields)
+		return
+	}
+	l.DebugFields(ctx, msg, fields...)
+}
+
+// InfoFields 记录 info 级别日志（强类型字段）
+func InfoFields(ctx context.Context, msg string, fields ...Field) {
+	mu.RLock()
+	l := defaultLogger
+	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.logFields(ctx, InfoLevel, msg, fields)
+		return
+	}
+	l.InfoFields(ctx, msg, fields...)
+}
+
+// WarnFields 记录 warn 级别日志（强类型字段）
+func WarnFields(ctx context.Context, msg string, fields ...Field) {
+	mu.RLock()
+	l := defaultLogger
+	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.logFields(ctx, WarnLevel, msg, fields)
+		return
+	}
+	l.WarnFields(ctx, msg, fields...)
+}
+
+// ErrorFields 记录 error 级别日志（强类型字段）
+func ErrorFields(ctx context.Context, msg string, fields ...Field) {
+	mu.RLock()
+	l := defaultLogger
+	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.logFields(ctx, ErrorLevel, msg, fields)
+		return
+	}
+	l.ErrorFields(ctx, msg, fields...)
+}
+
+// FatalFields 记录 fatal 级别日志（强类型字段）
+func FatalFields(ctx context.Context, msg string, fields ...Field) {
+	mu.RLock()
+	l := defaultLogger
+	mu.RUnlock()
+	if dl, ok := l.(directLogger); ok {
+		dl.logFields(ctx, FatalLevel, msg, fields)
+		return
+	}
+	l.FatalFields(ctx, msg, fields...)
+}
+
 // With 创建带预设字段的子 logger
 func With(fields ...any) Logger {
 	mu.RLock()
@@ -145,3 +252,55 @@ func Sync() error {
 	mu.RUnlock()
 	return l.Sync()
 }
+
+// SetLevel 调整默认 logger 的日志级别
+func SetLevel(level Level) {
+	mu.RLock()
+	l := defaultLogger
+	mu.RUnlock()
+	l.SetLevel(level)
+}
+
+// GetLevel 返回默认 logger 的日志级别
+func GetLevel() Level {
+	mu.RLock()
+	l := defaultLogger
+	mu.RUnlock()
+	return l.GetLevel()
+}
+
+// Named 基于默认 logger 创建模块 logger
+func Named(name string) Logger {
+	mu.RLock()
+	l := defaultLogger
+	mu.RUnlock()
+	return l.Named(name)
+}
+
+// moduleLeveler 支持模块级别规则管理的 logger
+type moduleLeveler interface {
+	SetModuleLevel(pattern string, level Level)
+	SetModuleLevels(spec string) error
+}
+
+// SetModuleLevel 为默认 logger 下匹配 pattern 的模块设置级别覆盖
+func SetModuleLevel(pattern string, level Level) {
+	mu.RLock()
+	l := defaultLogger
+	mu.RUnlock()
+	if ml, ok := l.(moduleLeveler); ok {
+		ml.SetModuleLevel(pattern, level)
+	}
+}
+
+// SetModuleLevels 用 spec（如 "orm.*=warn,http=debug"）整体替换默认 logger 的模块级别规则
+func SetModuleLevels(spec string) error {
+	mu.RLock()
+	l := defaultLogger
+	mu.RUnlock()
+	ml, ok := l.(moduleLeveler)
+	if !ok {
+		return fmt.Errorf("logger %T does not support module levels", l)
+	}
+	return ml.SetModuleLevels(spec)
+}
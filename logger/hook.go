@@ -0,0 +1,243 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// hookSyncTimeout Sync 和 Close 等待 hook 处理完已排队日志的最长时间
+const hookSyncTimeout = time.Second
+
+// Entry 传给 hook 的日志记录
+type Entry struct {
+	Context context.Context // 记录日志时传入的 context，可用于获取 trace 信息；没有时为 context.Background()
+	Level   Level
+	Time    time.Time
+	Logger  string // 模块名，根 logger 为空
+	Message string
+	Caller  string         // 调用位置，如 pkg/file.go:42
+	Stack   string         // 堆栈，仅在启用 WithStacktrace 时存在
+	Fields  map[string]any // 全部字段，包括 With 和 context 中的字段
+}
+
+// HookOption hook 选项
+type HookOption func(*hookConfig)
+
+// hookConfig hook 配置
+type hookConfig struct {
+	fn     func(Entry)
+	name   string
+	level  Level
+	buffer int
+}
+
+// WithHookLevel 设置触发 hook 的最低级别，默认 Error
+func WithHookLevel(level Level) HookOption {
+	return func(c *hookConfig) {
+		c.level = level
+	}
+}
+
+// WithHookBuffer 设置 hook 队列容量，默认 1024，队列满时丢弃并计入丢弃统计
+func WithHookBuffer(size int) HookOption {
+	return func(c *hookConfig) {
+		c.buffer = size
+	}
+}
+
+// WithHookName 设置 hook 名称，用于丢弃统计（dropped.hook:<name>），默认 "hook"
+func WithHookName(name string) HookOption {
+	return func(c *hookConfig) {
+		c.name = name
+	}
+}
+
+// hookItem hook 队列中的元素，done 不为 nil 时表示 Sync 请求
+type hookItem struct {
+	entry Entry
+	done  chan struct{}
+}
+
+// hookCore 将日志异步交给 hook 处理
+// 每个 hook 有独立的队列和处理协程，队列满时直接丢弃，不会阻塞记录日志的调用方
+// 处理协程在 logger 关闭时退出，With 创建的副本共享同一个队列和协程
+type hookCore struct {
+	cfg    *hookConfig
+	queue  chan hookItem
+	drops  *dropCounter
+	fields []zapcore.Field
+
+	stop     chan struct{} // 关闭信号
+	stopped  chan struct{} // 处理协程退出后关闭
+	stopOnce *sync.Once
+}
+
+// newHookCore 创建 hook core 并启动处理协程
+func newHookCore(cfg *hookConfig, drops *dropCounter) *hookCore {
+	c := &hookCore{
+		cfg:      cfg,
+		queue:    make(chan hookItem, cfg.buffer),
+		drops:    drops,
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+		stopOnce: &sync.Once{},
+	}
+	go c.run()
+	return c
+}
+
+// run 依次调用 hook，hook 中的 panic 被恢复并输出到 stderr，不影响后续日志
+// 收到关闭信号后处理完已排队的日志再退出
+func (c *hookCore) run() {
+	defer close(c.stopped)
+	for {
+		select {
+		case item := <-c.queue:
+			c.handle(item)
+		case <-c.stop:
+			for {
+				select {
+				case item := <-c.queue:
+					c.handle(item)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// handle 处理队列中的一个元素
+func (c *hookCore) handle(item hookItem) {
+	if item.done != nil {
+		close(item.done)
+		return
+	}
+	c.call(item.entry)
+}
+
+// close 停止处理协程，最多等待 hookSyncTimeout 处理完已排队的日志
+func (c *hookCore) close() error {
+	c.stopOnce.Do(func() { close(c.stop) })
+
+	timer := time.NewTimer(hookSyncTimeout)
+	defer timer.Stop()
+	select {
+	case <-c.stopped:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("hook %s: close timed out", c.cfg.name)
+	}
+}
+
+// call 调用 hook 并恢复 panic
+func (c *hookCore) call(e Entry) {
+	defer func() {
+		if r := recover(); r != nil {
+			internalLogf("hook %s panicked: %v", c.cfg.name, r)
+		}
+	}()
+	c.cfg.fn(e)
+}
+
+// Enabled 实现 zapcore.Core
+func (c *hookCore) Enabled(level zapcore.Level) bool {
+	return level >= zapLevel(c.cfg.level)
+}
+
+// With 实现 zapcore.Core
+func (c *hookCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field(nil), c.fields...), fields...)
+	return &clone
+}
+
+// Check 实现 zapcore.Core
+func (c *hookCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core，字段在调用方协程中编码，避免异步处理时字段值已被修改
+func (c *hookCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ctx := context.Background()
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		if hc, ok := f.Interface.(hookContext); ok && f.Type == zapcore.SkipType {
+			ctx = hc.Context
+			continue
+		}
+		f.AddTo(enc)
+	}
+
+	e := Entry{
+		Context: ctx,
+		Level:   fromZapLevel(ent.Level),
+		Time:    ent.Time,
+		Logger:  ent.LoggerName,
+		Message: ent.Message,
+		Stack:   ent.Stack,
+		Fields:  enc.Fields,
+	}
+	if ent.Caller.Defined {
+		e.Caller = ent.Caller.TrimmedPath()
+	}
+
+	select {
+	case <-c.stop:
+		return nil
+	default:
+	}
+	select {
+	case c.queue <- hookItem{entry: e}:
+	default:
+		c.drops.add("hook:" + c.cfg.name)
+	}
+	return nil
+}
+
+// Sync 实现 zapcore.Core，等待已排队的日志处理完成，最多等待 hookSyncTimeout
+func (c *hookCore) Sync() error {
+	done := make(chan struct{})
+	timer := time.NewTimer(hookSyncTimeout)
+	defer timer.Stop()
+
+	select {
+	case c.queue <- hookItem{done: done}:
+	case <-c.stopped:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("hook %s: sync timed out", c.cfg.name)
+	}
+	select {
+	case <-done:
+		return nil
+	case <-c.stopped:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("hook %s: sync timed out", c.cfg.name)
+	}
+}
+
+// hookContext 携带记录日志时的 context，仅在配置了 hook 时作为 SkipType 字段附加，编码器会忽略该字段
+type hookContext struct {
+	context.Context
+}
+
+// hookContextField 创建携带 context 的字段
+func hookContextField(ctx context.Context) zap.Field {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return zap.Field{Type: zapcore.SkipType, Interface: hookContext{ctx}}
+}
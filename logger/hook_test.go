@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type ctxKey struct{}
+
+// hookRecorder 收集 hook 收到的日志
+type hookRecorder struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+func (r *hookRecorder) hook(e Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+}
+
+func (r *hookRecorder) all() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+func TestWithHook(t *testing.T) {
+	var errs, all hookRecorder
+	l, err := New(
+		WithStdout(WithOutputLevel(FatalLevel)),
+		WithLevel(DebugLevel),
+		WithRedaction(),
+		WithHook(errs.hook),
+		WithHook(all.hook, WithHookLevel(DebugLevel)),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "v")
+	orders := l.Named("orders").With("request_id", "r-1")
+	orders.Debug(ctx, "debug")
+	orders.Warn(ctx, "warn")
+	orders.Error(ctx, "payment failed", "token", "secret-token", "amount", 12.5)
+	_ = l.Sync()
+
+	if got := len(all.all()); got != 3 {
+		t.Errorf("debug-level hook got %d entries, want 3", got)
+	}
+	entries := errs.all()
+	if len(entries) != 1 {
+		t.Fatalf("error-level hook got %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Level != ErrorLevel || e.Message != "payment failed" || e.Logger != "orders" {
+		t.Errorf("entry = %+v", e)
+	}
+	if e.Fields["request_id"] != "r-1" || e.Fields["token"] != "******" || e.Fields["amount"] != 12.5 {
+		t.Errorf("fields = %v", e.Fields)
+	}
+	if _, ok := e.Fields[""]; ok {
+		t.Errorf("context carrier leaked into fields: %v", e.Fields)
+	}
+	if e.Context.Value(ctxKey{}) != "v" {
+		t.Error("hook entry should carry the logging context")
+	}
+	if !strings.HasPrefix(e.Caller, "logger/hook_test.go:") {
+		t.Errorf("caller = %q", e.Caller)
+	}
+}
+
+func TestHookDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	var calls hookRecorder
+	l, err := New(
+		WithStdout(WithOutputLevel(FatalLevel)),
+		WithHook(func(e Entry) {
+			<-release
+			calls.hook(e)
+		}, WithHookBuffer(1), WithHookName("alert")),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		l.Error(context.Background(), "boom")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("logging blocked on a slow hook for %v", elapsed)
+	}
+
+	zl := l.(*zapLogger)
+	zl.drops.mu.Lock()
+	dropped := zl.drops.counts["hook:alert"]
+	zl.drops.mu.Unlock()
+	// 1 条正在处理，1 条在队列中，其余丢弃
+	if dropped < 8 {
+		t.Errorf("dropped = %d, want at least 8", dropped)
+	}
+
+	close(release)
+	_ = l.Sync()
+	if got := len(calls.all()); got+int(dropped) != 10 {
+		t.Errorf("handled %d + dropped %d, want 10", got, dropped)
+	}
+}
+
+func TestHookPanicRecovered(t *testing.T) {
+	var calls hookRecorder
+	l, err := New(
+		WithStdout(WithOutputLevel(FatalLevel)),
+		WithHook(func(e Entry) {
+			calls.hook(e)
+			if e.Message == "first" {
+				panic("hook failure")
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	l.Error(context.Background(), "first")
+	l.Error(context.Background(), "second")
+	_ = l.Sync()
+
+	if got := len(calls.all()); got != 2 {
+		t.Fatalf("hook called %d times, want 2", got)
+	}
+}
+
+func TestCloseStopsHookWorker(t *testing.T) {
+	var calls hookRecorder
+	l, err := New(WithStdout(WithOutputLevel(FatalLevel)), WithHook(calls.hook))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	child := l.Named("orders").With("request_id", "r-1")
+	child.Error(context.Background(), "queued")
+	_ = Close(l)
+
+	select {
+	case <-l.(*zapLogger).hooks[0].stopped:
+	default:
+		t.Fatal("hook worker should stop after Close")
+	}
+	if got := len(calls.all()); got != 1 {
+		t.Fatalf("hook called %d times before close, want 1", got)
+	}
+
+	// 关闭后子 logger 仍可记录日志，但不再交给 hook
+	child.Error(context.Background(), "after close")
+	if err := child.Sync(); err != nil && strings.Contains(err.Error(), "hook") {
+		t.Errorf("Sync after close: %v", err)
+	}
+	if got := len(calls.all()); got != 1 {
+		t.Errorf("hook called %d times after close, want 1", got)
+	}
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// levelPayload 级别接口的请求/响应体
+type levelPayload struct {
+	Level string `json:"level"`
+	Error string `json:"error,omitempty"`
+}
+
+// LevelHandler 返回查询和调整日志级别的 http.Handler
+//
+//	GET                          -> {"level":"info"}
+//	PUT {"level":"debug"}        -> {"level":"debug"}
+//	PUT ?level=debug 或表单参数  -> {"level":"debug"}
+//
+// 该接口可以改变线上日志量，挂载时应放在受保护的管理端口或路由下。
+func LevelHandler(l Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeLevel(w, http.StatusOK, levelPayload{Level: l.GetLevel().String()})
+
+		case http.MethodPut, http.MethodPost:
+			text := r.URL.Query().Get("level")
+			if text == "" {
+				if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+					var req levelPayload
+					if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+						writeLevel(w, http.StatusBadRequest, levelPayload{Error: "invalid json body: " + err.Error()})
+						return
+					}
+					text = req.Level
+				} else {
+					text = r.FormValue("level")
+				}
+			}
+
+			level, err := parseLevel(text)
+			if err != nil {
+				writeLevel(w, http.StatusBadRequest, levelPayload{Level: l.GetLevel().String(), Error: err.Error()})
+				return
+			}
+
+			old := l.GetLevel()
+			l.SetLevel(level)
+			if old != level {
+				l.Warn(r.Context(), "log level changed", "old_level", old.String(), "new_level", level.String())
+			}
+			writeLevel(w, http.StatusOK, levelPayload{Level: level.String()})
+
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			writeLevel(w, http.StatusMethodNotAllowed, levelPayload{Error: "method not allowed"})
+		}
+	})
+}
+
+// writeLevel 写入 JSON 响应
+func writeLevel(w http.ResponseWriter, status int, payload levelPayload) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+// BindLevel 将 logger 的级别绑定到外部来源，立即应用一次并在来源变化时自动更新，返回解除绑定的函数
+// lookup 返回空字符串或无法识别的级别时保持当前级别；subscribe 可以直接传入 config.OnChange
+func BindLevel(l Logger, lookup func() string, subscribe func(func()) func()) func() {
+	apply := func() {
+		text := lookup()
+		if text == "" {
+			return
+		}
+		level, err := parseLevel(text)
+		if err != nil {
+			l.Warn(context.Background(), "ignore invalid log level from source", "level", text)
+			return
+		}
+		if l.GetLevel() != level {
+			l.SetLevel(level)
+		}
+	}
+
+	apply()
+	if subscribe == nil {
+		return func() {}
+	}
+	return subscribe(apply)
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLevelHandler(t *testing.T) {
+	l, err := New(WithLevel(InfoLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := LevelHandler(l)
+
+	do := func(method, target, body, contentType string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodGet, "/", "", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"info"`) {
+		t.Fatalf("unexpected GET response: %d %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := do(http.MethodPut, "/", `{"level":"debug"}`, "application/json"); rec.Code != http.StatusOK {
+		t.Fatalf("unexpected PUT response: %d %s", rec.Code, rec.Body.String())
+	}
+	if l.GetLevel() != DebugLevel {
+		t.Fatalf("expected debug level, got %s", l.GetLevel())
+	}
+
+	if rec := do(http.MethodPut, "/?level=bogus", "", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid level, got %d", rec.Code)
+	}
+	if l.GetLevel() != DebugLevel {
+		t.Fatal("invalid level should not change current level")
+	}
+
+	if rec := do(http.MethodDelete, "/", "", ""); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rec.Code)
+	}
+}
+
+func TestBindLevel(t *testing.T) {
+	l, err := New(WithLevel(InfoLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value := "warn"
+	var notify func()
+	stop := BindLevel(l, func() string { return value }, func(cb func()) func() {
+		notify = cb
+		return func() { notify = nil }
+	})
+
+	if l.GetLevel() != WarnLevel {
+		t.Fatalf("expected warn after bind, got %s", l.GetLevel())
+	}
+
+	value = "error"
+	notify()
+	if l.GetLevel() != ErrorLevel {
+		t.Fatalf("expected error after change, got %s", l.GetLevel())
+	}
+
+	stop()
+	if notify != nil {
+		t.Fatal("expected subscription to be cancelled")
+	}
+}
@@ -0,0 +1,259 @@
+package logger
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// logfmtPool logfmt 编码使用的缓冲池
+var logfmtPool = buffer.NewPool()
+
+// logfmtEncoder logfmt 格式编码器：每行为空格分隔的 key=value
+//
+// 嵌套对象展开为 a.b=value，数组和无法展开的值编码为 JSON 字符串。
+type logfmtEncoder struct {
+	cfg        zapcore.EncoderConfig
+	buf        *buffer.Buffer // With 添加的字段，每个字段以空格开头
+	namespaces []string
+}
+
+// newLogfmtEncoder 创建 logfmt 编码器
+func newLogfmtEncoder(cfg zapcore.EncoderConfig) *logfmtEncoder {
+	return &logfmtEncoder{cfg: cfg, buf: logfmtPool.Get()}
+}
+
+// Clone 实现 zapcore.Encoder
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := &logfmtEncoder{cfg: e.cfg, buf: logfmtPool.Get()}
+	clone.buf.Write(e.buf.Bytes())
+	clone.namespaces = append([]string(nil), e.namespaces...)
+	return clone
+}
+
+// EncodeEntry 实现 zapcore.Encoder
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line := logfmtPool.Get()
+
+	if e.cfg.TimeKey != "" {
+		line.AppendString(e.cfg.TimeKey)
+		line.AppendByte('=')
+		line.AppendString(ent.Time.Format("2006-01-02T15:04:05.000Z0700"))
+	}
+	if e.cfg.LevelKey != "" {
+		appendLogfmtPair(line, e.cfg.LevelKey, ent.Level.String())
+	}
+	if e.cfg.NameKey != "" && ent.LoggerName != "" {
+		appendLogfmtPair(line, e.cfg.NameKey, ent.LoggerName)
+	}
+	if e.cfg.CallerKey != "" && ent.Caller.Defined {
+		appendLogfmtPair(line, e.cfg.CallerKey, ent.Caller.TrimmedPath())
+	}
+	if e.cfg.MessageKey != "" {
+		appendLogfmtPair(line, e.cfg.MessageKey, ent.Message)
+	}
+
+	line.Write(e.buf.Bytes())
+	// 限制容量，字段中的命名空间追加到新数组，不与并发的 EncodeEntry 共享
+	n := len(e.namespaces)
+	enc := &logfmtEncoder{cfg: e.cfg, buf: line, namespaces: e.namespaces[:n:n]}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	if e.cfg.StacktraceKey != "" && ent.Stack != "" {
+		appendLogfmtPair(line, e.cfg.StacktraceKey, ent.Stack)
+	}
+	line.AppendString(e.cfg.LineEnding)
+	if e.cfg.LineEnding == "" {
+		line.AppendByte('\n')
+	}
+	return line, nil
+}
+
+// key 返回带命名空间前缀的 key
+func (e *logfmtEncoder) key(key string) string {
+	if len(e.namespaces) == 0 {
+		return key
+	}
+	return strings.Join(e.namespaces, ".") + "." + key
+}
+
+// addRaw 写入不需要引号的值
+func (e *logfmtEncoder) addRaw(key, value string) {
+	e.buf.AppendByte(' ')
+	e.buf.AppendString(logfmtKey(e.key(key)))
+	e.buf.AppendByte('=')
+	e.buf.AppendString(value)
+}
+
+// addJSON 将值编码为 JSON 字符串写入
+func (e *logfmtEncoder) addJSON(key string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		e.AddString(key+"Error", err.Error())
+		return
+	}
+	e.AddString(key, string(data))
+}
+
+func (e *logfmtEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	m := zapcore.NewMapObjectEncoder()
+	if err := m.AddArray(key, arr); err != nil {
+		return err
+	}
+	e.addJSON(key, m.Fields[key])
+	return nil
+}
+
+func (e *logfmtEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	e.namespaces = append(e.namespaces, key)
+	err := obj.MarshalLogObject(e)
+	e.namespaces = e.namespaces[:len(e.namespaces)-1]
+	return err
+}
+
+func (e *logfmtEncoder) AddBinary(key string, value []byte) {
+	e.AddString(key, base64.StdEncoding.EncodeToString(value))
+}
+
+func (e *logfmtEncoder) AddByteString(key string, value []byte) {
+	e.AddString(key, string(value))
+}
+
+func (e *logfmtEncoder) AddBool(key string, value bool) {
+	e.addRaw(key, strconv.FormatBool(value))
+}
+
+func (e *logfmtEncoder) AddComplex128(key string, value complex128) {
+	e.addRaw(key, strings.Trim(strconv.FormatComplex(value, 'f', -1, 128), "()"))
+}
+
+func (e *logfmtEncoder) AddComplex64(key string, value complex64) {
+	e.addRaw(key, strings.Trim(strconv.FormatComplex(complex128(value), 'f', -1, 64), "()"))
+}
+
+func (e *logfmtEncoder) AddDuration(key string, value time.Duration) {
+	e.addRaw(key, value.String())
+}
+
+func (e *logfmtEncoder) AddFloat64(key string, value float64) {
+	e.addRaw(key, formatLogfmtFloat(value, 64))
+}
+
+func (e *logfmtEncoder) AddFloat32(key string, value float32) {
+	e.addRaw(key, formatLogfmtFloat(float64(value), 32))
+}
+
+func (e *logfmtEncoder) AddInt(key string, value int)     { e.AddInt64(key, int64(value)) }
+func (e *logfmtEncoder) AddInt32(key string, value int32) { e.AddInt64(key, int64(value)) }
+func (e *logfmtEncoder) AddInt16(key string, value int16) { e.AddInt64(key, int64(value)) }
+func (e *logfmtEncoder) AddInt8(key string, value int8)   { e.AddInt64(key, int64(value)) }
+
+func (e *logfmtEncoder) AddInt64(key string, value int64) {
+	e.addRaw(key, strconv.FormatInt(value, 10))
+}
+
+func (e *logfmtEncoder) AddString(key, value string) {
+	e.buf.AppendByte(' ')
+	appendLogfmtValue(e.buf, logfmtKey(e.key(key)), value)
+}
+
+func (e *logfmtEncoder) AddTime(key string, value time.Time) {
+	e.addRaw(key, value.Format("2006-01-02T15:04:05.000Z0700"))
+}
+
+func (e *logfmtEncoder) AddUint(key string, value uint)       { e.AddUint64(key, uint64(value)) }
+func (e *logfmtEncoder) AddUint32(key string, value uint32)   { e.AddUint64(key, uint64(value)) }
+func (e *logfmtEncoder) AddUint16(key string, value uint16)   { e.AddUint64(key, uint64(value)) }
+func (e *logfmtEncoder) AddUint8(key string, value uint8)     { e.AddUint64(key, uint64(value)) }
+func (e *logfmtEncoder) AddUintptr(key string, value uintptr) { e.AddUint64(key, uint64(value)) }
+
+func (e *logfmtEncoder) AddUint64(key string, value uint64) {
+	e.addRaw(key, strconv.FormatUint(value, 10))
+}
+
+func (e *logfmtEncoder) AddReflected(key string, value any) error {
+	switch v := value.(type) {
+	case string:
+		e.AddString(key, v)
+	case fmt.Stringer:
+		e.AddString(key, v.String())
+	default:
+		e.addJSON(key, v)
+	}
+	return nil
+}
+
+func (e *logfmtEncoder) OpenNamespace(key string) {
+	e.namespaces = append(e.namespaces, key)
+}
+
+// appendLogfmtPair 写入以空格开头的 key=value
+func appendLogfmtPair(buf *buffer.Buffer, key, value string) {
+	if buf.Len() > 0 {
+		buf.AppendByte(' ')
+	}
+	appendLogfmtValue(buf, key, value)
+}
+
+// appendLogfmtValue 写入 key=value，值包含空格、引号、等号或控制字符时加引号
+func appendLogfmtValue(buf *buffer.Buffer, key, value string) {
+	buf.AppendString(key)
+	buf.AppendByte('=')
+	if logfmtNeedsQuote(value) {
+		buf.AppendString(strconv.Quote(value))
+		return
+	}
+	buf.AppendString(value)
+}
+
+// logfmtNeedsQuote 判断值是否需要加引号
+func logfmtNeedsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// logfmtKey 将 key 中的非法字符替换为下划线
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	if !strings.ContainsAny(key, " =\"\t\n\r") {
+		return key
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// formatLogfmtFloat 格式化浮点数，NaN 和 Inf 使用字符串表示
+func formatLogfmtFloat(v float64, bits int) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'f', -1, bits)
+}
@@ -1,6 +1,10 @@
 package logger
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"strings"
+)
 
 // Logger 日志接口
 type Logger interface {
@@ -18,13 +22,39 @@ type Logger interface {
 	ErrorMap(ctx context.Context, msg string, fields map[string]any)
 	FatalMap(ctx context.Context, msg string, fields map[string]any)
 
+	// 强类型字段方式，字段不经过 any 装箱，适合热点路径
+	DebugFields(ctx context.Context, msg string, fields ...Field)
+	InfoFields(ctx context.Context, msg string, fields ...Field)
+	WarnFields(ctx context.Context, msg string, fields ...Field)
+	ErrorFields(ctx context.Context, msg string, fields ...Field)
+	FatalFields(ctx context.Context, msg string, fields ...Field)
+
 	// With 方法创建带预设字段的子 logger
 	With(fields ...any) Logger
 
+	// Named 创建模块 logger，日志带 logger 字段，可单独设置级别
+	Named(name string) Logger
+
+	// SetLevel 运行时调整日志级别，对该 logger 及其子 logger 立即生效
+	// 对 Named 创建的模块 logger 调用时，仅为该模块设置级别覆盖
+	SetLevel(level Level)
+	// GetLevel 返回当前日志级别
+	GetLevel() Level
+
 	// Sync 刷新缓冲区
 	Sync() error
 }
 
+// Close 关闭 logger 持有的后台资源：刷新全部输出，停止 hook 处理协程，关闭 OTLP 导出器和文件切割器
+// 关闭作用于 logger 及其 With、Named 创建的全部子 logger，之后的日志不再交给 hook、OTLP 和内置切割的文件
+// 不支持关闭的 logger 实现只调用 Sync
+func Close(l Logger) error {
+	if c, ok := l.(interface{ Close() error }); ok {
+		return c.Close()
+	}
+	return l.Sync()
+}
+
 // Level 日志级别
 type Level int8
 
@@ -54,29 +84,55 @@ func (l Level) String() string {
 	}
 }
 
-// ParseLevel 从字符串解析日志级别
+// ParseLevel 从字符串解析日志级别，无法识别时返回 InfoLevel
 func ParseLevel(s string) Level {
-	switch s {
+	level, err := parseLevel(s)
+	if err != nil {
+		return InfoLevel
+	}
+	return level
+}
+
+// parseLevel 从字符串解析日志级别（不区分大小写），无法识别时返回错误
+func parseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
 	case "debug":
-		return DebugLevel
+		return DebugLevel, nil
 	case "info":
-		return InfoLevel
+		return InfoLevel, nil
 	case "warn", "warning":
-		return WarnLevel
+		return WarnLevel, nil
 	case "error":
-		return ErrorLevel
+		return ErrorLevel, nil
 	case "fatal":
-		return FatalLevel
+		return FatalLevel, nil
 	default:
-		return InfoLevel
+		return InfoLevel, fmt.Errorf("unrecognized log level: %q", s)
 	}
 }
 
+// MarshalText 实现 encoding.TextMarshaler
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler，无法识别的级别返回错误
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := parseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
+}
+
 // Format 日志格式
 type Format string
 
 const (
 	JSONFormat    Format = "json"
 	ConsoleFormat Format = "console"
+	LogfmtFormat  Format = "logfmt" // key=value 格式
+	ECSFormat     Format = "ecs"    // Elastic Common Schema JSON
+	GCPFormat     Format = "gcp"    // Google Cloud Logging 结构化 JSON
 )
-
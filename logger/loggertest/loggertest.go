@@ -0,0 +1,225 @@
+// Package loggertest 提供 logger 包的测试辅助工具
+//
+// New 返回的 Recorder 实现 logger.Logger，日志经过与正式 logger 相同的处理（级别、模块、脱敏等）
+// 后记录在内存中，可以直接断言结构化字段，不需要解析 stdout：
+//
+//	log := loggertest.New(t)
+//	client := httpclient.New(httpclient.WithLogger(log))
+//	...
+//	log.AssertLogged(t, logger.InfoLevel, "HTTP request completed", map[string]any{
+//	    "http.status_code": 200,
+//	})
+package loggertest
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Si40Code/kit/logger"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// Entry 记录的一条日志
+type Entry struct {
+	Level   logger.Level
+	Logger  string // 模块名，根 logger 为空
+	Message string
+	Time    time.Time
+	Caller  string         // 调用位置，如 pkg/file.go:42
+	Fields  map[string]any // 全部字段，包括 With 添加的字段
+}
+
+// Recorder 记录日志的 Logger
+type Recorder struct {
+	logger.Logger
+	logs *observer.ObservedLogs
+
+	mu     sync.Mutex
+	offset int // Reset 之前的日志条数
+}
+
+// New 创建记录日志的 Logger，默认级别为 Debug，可以通过 opts 调整（如 logger.WithRedaction）
+// 默认不输出到 stdout，Fatal 日志引发 logger.FatalPanic 而不是退出进程；
+// 测试失败时通过 t.Log 输出已记录的日志便于排查
+func New(t testing.TB, opts ...logger.Option) *Recorder {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	opts = append([]logger.Option{logger.WithLevel(logger.DebugLevel), logger.WithFatalPanic()}, opts...)
+	opts = append(opts, logger.WithCore(core))
+	l, err := logger.New(opts...)
+	if err != nil {
+		t.Fatalf("loggertest: create logger failed: %v", err)
+	}
+
+	r := &Recorder{Logger: l, logs: logs}
+	t.Cleanup(func() {
+		_ = l.Sync()
+		if t.Failed() {
+			for _, e := range r.Entries() {
+				t.Logf("loggertest: %s %s %q %v", e.Level, e.Logger, e.Message, e.Fields)
+			}
+		}
+	})
+	return r
+}
+
+// Entries 返回已记录的日志，按记录顺序排列
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	offset := r.offset
+	r.mu.Unlock()
+
+	all := r.logs.All()
+	if offset > len(all) {
+		offset = len(all)
+	}
+	entries := make([]Entry, 0, len(all)-offset)
+	for _, e := range all[offset:] {
+		entries = append(entries, Entry{
+			Level:   fromZapLevel(e.Level),
+			Logger:  e.LoggerName,
+			Message: e.Message,
+			Time:    e.Time,
+			Caller:  e.Caller.TrimmedPath(),
+			Fields:  e.ContextMap(),
+		})
+	}
+	return entries
+}
+
+// Filter 返回级别不低于 level 且消息包含 msgSubstring 的日志
+func (r *Recorder) Filter(level logger.Level, msgSubstring string) []Entry {
+	var matched []Entry
+	for _, e := range r.Entries() {
+		if e.Level >= level && strings.Contains(e.Message, msgSubstring) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// Reset 清空已记录的日志
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.offset = r.logs.Len()
+}
+
+// AssertLogged 断言存在级别为 level、消息包含 msgSubstring 且包含 fields 中全部字段的日志，返回第一条匹配的日志
+// 字段值按 JSON 编码后比较，200 与 int64(200)、error 与其错误信息视为相等；fields 为 nil 时只匹配级别和消息
+func (r *Recorder) AssertLogged(t testing.TB, level logger.Level, msgSubstring string, fields map[string]any) Entry {
+	t.Helper()
+
+	entries := r.Entries()
+	for _, e := range entries {
+		if e.Level == level && strings.Contains(e.Message, msgSubstring) && hasFields(e.Fields, fields) {
+			return e
+		}
+	}
+
+	var b strings.Builder
+	for _, e := range entries {
+		b.WriteString("\n  ")
+		b.WriteString(e.Level.String())
+		b.WriteString(" ")
+		b.WriteString(e.Message)
+		b.WriteString(" ")
+		data, _ := json.Marshal(e.Fields)
+		b.Write(data)
+	}
+	t.Fatalf("loggertest: no %s entry containing %q with fields %v; recorded:%s",
+		level, msgSubstring, fields, b.String())
+	return Entry{}
+}
+
+// AssertNotLogged 断言不存在级别不低于 level 且消息包含 msgSubstring 的日志
+func (r *Recorder) AssertNotLogged(t testing.TB, level logger.Level, msgSubstring string) {
+	t.Helper()
+
+	if matched := r.Filter(level, msgSubstring); len(matched) > 0 {
+		t.Fatalf("loggertest: unexpected %s entry %q: %v",
+			matched[0].Level, matched[0].Message, matched[0].Fields)
+	}
+}
+
+// AssertFatal 断言 fn 记录了 Fatal 日志，返回该日志
+// Fatal 引发的 panic 在这里恢复，之后的代码不会执行；其他 panic 继续向上抛出
+func (r *Recorder) AssertFatal(t testing.TB, fn func()) Entry {
+	t.Helper()
+
+	var fatal *logger.FatalPanic
+	func() {
+		defer func() {
+			if v := recover(); v != nil {
+				p, ok := v.(logger.FatalPanic)
+				if !ok {
+					panic(v)
+				}
+				fatal = &p
+			}
+		}()
+		fn()
+	}()
+
+	if fatal == nil {
+		t.Fatalf("loggertest: expected a fatal entry")
+	}
+	return r.AssertLogged(t, logger.FatalLevel, fatal.Message, nil)
+}
+
+// hasFields 判断 got 是否包含 want 中的全部字段
+func hasFields(got, want map[string]any) bool {
+	for k, v := range want {
+		actual, ok := got[k]
+		if !ok || !equalValue(actual, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalValue 按 JSON 编码比较字段值，忽略数值类型的差异
+func equalValue(actual, want any) bool {
+	if err, ok := want.(error); ok {
+		want = err.Error()
+	}
+	if reflect.DeepEqual(actual, want) {
+		return true
+	}
+	a, err1 := normalize(actual)
+	w, err2 := normalize(want)
+	return err1 == nil && err2 == nil && reflect.DeepEqual(a, w)
+}
+
+// normalize 通过 JSON 编解码统一值的类型
+func normalize(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+// fromZapLevel 将 zap 日志级别转换为 logger.Level
+func fromZapLevel(level zapcore.Level) logger.Level {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return logger.DebugLevel
+	case level == zapcore.InfoLevel:
+		return logger.InfoLevel
+	case level == zapcore.WarnLevel:
+		return logger.WarnLevel
+	case level < zapcore.FatalLevel:
+		return logger.ErrorLevel
+	default:
+		return logger.FatalLevel
+	}
+}
@@ -0,0 +1,84 @@
+package loggertest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Si40Code/kit/logger"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	log := New(t)
+
+	log.With("request_id", "r-1").Named("orders").Info(ctx, "order created", "order_id", 42, "amount", 12.5)
+	log.Error(ctx, "payment failed", errors.New("card declined"))
+	log.Debug(ctx, "debug enabled by default")
+
+	entries := log.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries", len(entries))
+	}
+	e := entries[0]
+	if e.Level != logger.InfoLevel || e.Logger != "orders" || e.Message != "order created" {
+		t.Errorf("entry = %+v", e)
+	}
+	if !strings.HasPrefix(e.Caller, "loggertest/loggertest_test.go:") {
+		t.Errorf("caller = %q", e.Caller)
+	}
+
+	log.AssertLogged(t, logger.InfoLevel, "created", map[string]any{
+		"request_id": "r-1",
+		"order_id":   42,
+		"amount":     12.5,
+	})
+	log.AssertLogged(t, logger.ErrorLevel, "payment", map[string]any{"error": errors.New("card declined")})
+	log.AssertNotLogged(t, logger.WarnLevel, "created")
+
+	if got := log.Filter(logger.InfoLevel, ""); len(got) != 2 {
+		t.Errorf("Filter returned %d entries", len(got))
+	}
+
+	log.Reset()
+	if got := log.Entries(); len(got) != 0 {
+		t.Errorf("after Reset got %d entries", len(got))
+	}
+	log.Warn(ctx, "after reset")
+	log.AssertLogged(t, logger.WarnLevel, "after reset", nil)
+}
+
+func TestRecorderOptions(t *testing.T) {
+	log := New(t, logger.WithLevel(logger.WarnLevel), logger.WithRedaction())
+
+	log.Info(context.Background(), "filtered")
+	log.Warn(context.Background(), "login", "password", "hunter2")
+
+	log.AssertNotLogged(t, logger.DebugLevel, "filtered")
+	log.AssertLogged(t, logger.WarnLevel, "login", map[string]any{"password": "******"})
+}
+
+func TestHasFields(t *testing.T) {
+	got := map[string]any{"status": int64(200), "tags": []any{"a"}, "name": "x"}
+	if !hasFields(got, map[string]any{"status": 200, "tags": []string{"a"}}) {
+		t.Error("expected numeric and slice values to match")
+	}
+	if hasFields(got, map[string]any{"status": 404}) {
+		t.Error("expected mismatched value to fail")
+	}
+	if hasFields(got, map[string]any{"missing": nil}) {
+		t.Error("expected missing key to fail")
+	}
+}
+
+func TestAssertFatal(t *testing.T) {
+	log := New(t)
+
+	e := log.AssertFatal(t, func() {
+		log.Fatal(context.Background(), "config missing", "key", "db.dsn")
+	})
+	if e.Fields["key"] != "db.dsn" {
+		t.Errorf("fields = %v", e.Fields)
+	}
+}
@@ -0,0 +1,28 @@
+package logger
+
+import "time"
+
+// MetricData OTLP 输出的导出指标数据
+type MetricData struct {
+	// 基础信息
+	Endpoint string // OTLP 端点
+	Records  int    // 本次导出的日志条数
+	Replay   bool   // 是否为重放（内存重试队列或磁盘 spool）
+
+	// 导出结果
+	Success  bool          // 是否成功
+	Duration time.Duration // 导出耗时
+	Error    error         // 错误（如果有）
+
+	// 输出状态
+	QueueLength int    // 内存队列中等待发送的条数
+	Dropped     uint64 // 累计丢弃条数
+	Spooled     uint64 // 当前磁盘 spool 中的条数
+}
+
+// MetricRecorder OTLP 输出指标记录器接口
+type MetricRecorder interface {
+	// RecordExport 记录一次 OTLP 导出
+	// 实现者可以将数据发送到 Prometheus、SigNoz 或其他监控系统
+	RecordExport(data MetricData)
+}
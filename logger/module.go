@@ -0,0 +1,214 @@
+package logger
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// moduleLevels 模块级别覆盖规则，由同一个根 logger 派生的所有 logger 共享
+//
+// 规则的 pattern 支持三种写法：
+//   - 精确名称：orm.payments
+//   - 通配符：orm.*（语法同 path.Match）
+//   - 前缀：orm（同时作用于 orm 及其所有子模块 orm.xxx）
+//
+// 多条规则同时命中时，精确匹配优先，其次取 pattern 最长（最具体）的一条。
+type moduleLevels struct {
+	mu      sync.RWMutex
+	rules   map[string]Level
+	version atomic.Uint64
+}
+
+// newModuleLevels 创建模块级别规则表
+func newModuleLevels(rules map[string]Level) *moduleLevels {
+	m := &moduleLevels{rules: make(map[string]Level, len(rules))}
+	for pattern, level := range rules {
+		m.rules[pattern] = level
+	}
+	return m
+}
+
+// set 设置单条规则
+func (m *moduleLevels) set(pattern string, level Level) {
+	m.mu.Lock()
+	m.rules[pattern] = level
+	m.mu.Unlock()
+	m.version.Add(1)
+}
+
+// replace 整体替换规则
+func (m *moduleLevels) replace(rules map[string]Level) {
+	m.mu.Lock()
+	m.rules = make(map[string]Level, len(rules))
+	for pattern, level := range rules {
+		m.rules[pattern] = level
+	}
+	m.mu.Unlock()
+	m.version.Add(1)
+}
+
+// snapshot 返回规则副本
+func (m *moduleLevels) snapshot() map[string]Level {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rules := make(map[string]Level, len(m.rules))
+	for pattern, level := range m.rules {
+		rules[pattern] = level
+	}
+	return rules
+}
+
+// lookup 查找模块的级别覆盖，未命中任何规则时返回 false
+func (m *moduleLevels) lookup(name string) (Level, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if level, ok := m.rules[name]; ok {
+		return level, true
+	}
+
+	var (
+		best    string
+		level   Level
+		matched bool
+	)
+	for pattern, l := range m.rules {
+		if !matchModule(pattern, name) {
+			continue
+		}
+		if !matched || len(pattern) > len(best) || (len(pattern) == len(best) && pattern < best) {
+			best, level, matched = pattern, l, true
+		}
+	}
+	return level, matched
+}
+
+// matchModule 判断模块名是否命中 pattern
+func matchModule(pattern, name string) bool {
+	if pattern == "" || name == "" {
+		return false
+	}
+	if strings.HasPrefix(name, pattern+".") {
+		return true
+	}
+	ok, err := path.Match(pattern, name)
+	return err == nil && ok
+}
+
+// ParseModuleLevels 解析模块级别配置，如 "orm.*=warn,http=debug"
+// 多条规则之间可以用逗号、分号或空白分隔
+func ParseModuleLevels(spec string) (map[string]Level, error) {
+	rules := make(map[string]Level)
+	items := strings.FieldsFunc(spec, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\t' || r == '\n'
+	})
+	for _, item := range items {
+		pattern, text, ok := strings.Cut(item, "=")
+		pattern = strings.TrimSpace(pattern)
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("invalid module level %q, expected pattern=level", item)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid module pattern %q: %w", pattern, err)
+		}
+		level, err := parseLevel(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid module level %q: %w", item, err)
+		}
+		rules[pattern] = level
+	}
+	return rules, nil
+}
+
+// moduleEnabler 计算某个模块的生效级别：命中模块规则时使用规则级别，否则跟随根级别
+type moduleEnabler struct {
+	name    string
+	modules *moduleLevels
+	root    zap.AtomicLevel
+	cache   atomic.Pointer[moduleCache]
+}
+
+// moduleCache 缓存规则查找结果，规则变化时失效
+type moduleCache struct {
+	version uint64
+	level   Level
+	ok      bool
+}
+
+// newModuleEnabler 创建模块级别判定器，name 为空表示根 logger
+func newModuleEnabler(name string, modules *moduleLevels, root zap.AtomicLevel) *moduleEnabler {
+	return &moduleEnabler{name: name, modules: modules, root: root}
+}
+
+// override 返回模块级别覆盖
+func (e *moduleEnabler) override() (Level, bool) {
+	if e.name == "" {
+		return 0, false
+	}
+
+	version := e.modules.version.Load()
+	if c := e.cache.Load(); c != nil && c.version == version {
+		return c.level, c.ok
+	}
+
+	level, ok := e.modules.lookup(e.name)
+	e.cache.Store(&moduleCache{version: version, level: level, ok: ok})
+	return level, ok
+}
+
+// Level 返回生效级别
+func (e *moduleEnabler) Level() zapcore.Level {
+	if level, ok := e.override(); ok {
+		return zapLevel(level)
+	}
+	return e.root.Level()
+}
+
+// Enabled 实现 zapcore.LevelEnabler
+func (e *moduleEnabler) Enabled(level zapcore.Level) bool {
+	return e.Level().Enabled(level)
+}
+
+// levelFilterCore 在输出 cores 之外按 logger 维度过滤级别
+// 输出 cores 以 Debug 级别创建，实际生效级别由外层的 enabler 决定
+type levelFilterCore struct {
+	zapcore.Core
+	enabler *moduleEnabler
+}
+
+// newLevelFilterCore 包装 core，若已被包装则替换 enabler
+func newLevelFilterCore(core zapcore.Core, enabler *moduleEnabler) zapcore.Core {
+	if f, ok := core.(*levelFilterCore); ok {
+		core = f.Core
+	}
+	return &levelFilterCore{Core: core, enabler: enabler}
+}
+
+// Level 供 zapcore.LevelOf 使用
+func (c *levelFilterCore) Level() zapcore.Level {
+	return c.enabler.Level()
+}
+
+// Enabled 实现 zapcore.Core
+func (c *levelFilterCore) Enabled(level zapcore.Level) bool {
+	return c.enabler.Enabled(level) && c.Core.Enabled(level)
+}
+
+// With 实现 zapcore.Core
+func (c *levelFilterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelFilterCore{Core: c.Core.With(fields), enabler: c.enabler}
+}
+
+// Check 实现 zapcore.Core
+func (c *levelFilterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.enabler.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newObservedLogger 创建输出到 observer 的 logger，用于断言日志内容
+func newObservedLogger(t *testing.T, opts ...Option) (*zapLogger, *observer.ObservedLogs) {
+	t.Helper()
+
+	options := newOptions(opts...)
+	if options.err != nil {
+		t.Fatal(options.err)
+	}
+	level := zap.NewAtomicLevelAt(zapLevel(options.level))
+	modules := newModuleLevels(options.moduleLevels)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	zlog := zap.New(newLevelFilterCore(core, newModuleEnabler("", modules, level)))
+	return &zapLogger{
+		logger:  zlog,
+		opts:    options,
+		level:   level,
+		modules: modules,
+	}, logs
+}
+
+func TestNamedModuleLevels(t *testing.T) {
+	ctx := context.Background()
+	root, logs := newObservedLogger(t, WithLevel(InfoLevel), WithModuleLevels("orm.*=warn, http=debug"))
+
+	payments := root.Named("orm").Named("payments")
+	payments.Info(ctx, "dropped by orm.* rule")
+	payments.Warn(ctx, "kept")
+
+	client := root.Named("http").Named("client")
+	client.Debug(ctx, "kept by http prefix rule")
+
+	root.Debug(ctx, "dropped by root level")
+
+	entries := logs.TakeAll()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %+v", len(entries), entries)
+	}
+	if entries[0].LoggerName != "orm.payments" || entries[0].Message != "kept" {
+		t.Fatalf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].LoggerName != "http.client" {
+		t.Fatalf("unexpected logger name: %q", entries[1].LoggerName)
+	}
+
+	// 模块 logger 的 SetLevel 仅影响自身
+	payments.SetLevel(DebugLevel)
+	payments.With("order_id", 1).Debug(ctx, "now enabled")
+	root.Named("orm").Named("users").Info(ctx, "still dropped")
+	if root.GetLevel() != InfoLevel {
+		t.Fatalf("root level changed: %s", root.GetLevel())
+	}
+
+	entries = logs.TakeAll()
+	if len(entries) != 1 || entries[0].Message != "now enabled" {
+		t.Fatalf("unexpected entries after SetLevel: %+v", entries)
+	}
+
+	// 清空规则后回到根级别
+	if err := root.SetModuleLevels(""); err != nil {
+		t.Fatal(err)
+	}
+	if got := payments.GetLevel(); got != InfoLevel {
+		t.Fatalf("expected module to follow root level, got %s", got)
+	}
+}
+
+func TestParseModuleLevels(t *testing.T) {
+	rules, err := ParseModuleLevels("orm.*=warn;http=DEBUG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules["orm.*"] != WarnLevel || rules["http"] != DebugLevel {
+		t.Fatalf("unexpected rules: %v", rules)
+	}
+
+	for _, spec := range []string{"orm", "orm=verbose", "[=info"} {
+		if _, err := ParseModuleLevels(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+
+	if _, err := New(WithModuleLevels("orm=verbose")); err == nil {
+		t.Fatal("expected New to reject invalid module levels")
+	}
+}
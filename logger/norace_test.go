@@ -0,0 +1,6 @@
+//go:build !race
+
+package logger
+
+// raceEnabled 是否启用了竞态检测，竞态检测会改变内存分配次数
+const raceEnabled = false
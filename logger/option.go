@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.uber.org/zap/zapcore"
 )
 
 // Option 配置选项函数
@@ -13,6 +15,7 @@ type Option func(*options)
 type options struct {
 	level              Level
 	format             Format
+	gcpProject         string // GCPFormat 中 trace 字段使用的项目 ID
 	outputs            []Output
 	enableTrace        bool
 	serviceName        string
@@ -20,6 +23,36 @@ type options struct {
 	caller             bool
 	stacktrace         bool
 	resourceAttributes []attribute.KeyValue
+
+	// 动态级别来源
+	levelLookup    func() string
+	levelSubscribe func(func()) func()
+
+	// 模块级别覆盖
+	moduleLevels map[string]Level
+
+	// 选项解析错误，由 New 返回
+	err error
+
+	// 严格字段校验：格式错误的 key-value 对会额外输出一条带调用位置的警告
+	strictFields bool
+
+	// 采样与丢弃统计
+	sampling            *samplingConfig
+	dropSummaryInterval time.Duration
+
+	// 是否仍使用默认的 stdout 输出，显式调用 WithStdout 时替换
+	implicitStdout bool
+
+	// 字段脱敏，nil 表示不脱敏
+	redactor *redactor
+
+	// 日志 hook
+	hooks []*hookConfig
+
+	// Fatal 处理
+	fatalPanic  bool
+	exitTimeout time.Duration
 }
 
 // Output 输出配置
@@ -35,34 +68,68 @@ const (
 	StdoutOutput OutputType = "stdout"
 	FileOutput   OutputType = "file"
 	OTLPOutput   OutputType = "otlp"
+	OTelOutput   OutputType = "otel" // 桥接到 OpenTelemetry logs SDK
+	CoreOutput   OutputType = "core" // 自定义 zapcore.Core
 )
 
 // OutputConfig 输出配置详情
 type OutputConfig struct {
 	// File 配置
-	FilePath   string
-	MaxSize    int  // MB
-	MaxAge     int  // days
-	MaxBackups int  // 保留文件数量
-	Compress   bool // 是否压缩
+	FilePath       string            // 文件路径，文件名可包含 %Y %m %d %H %M %S 占位符
+	MaxSize        int               // MB
+	MaxAge         int               // days
+	MaxBackups     int               // 保留文件数量
+	Compress       bool              // 是否压缩
+	RotateInterval time.Duration     // 按时间切割的周期，0 表示不按时间切割
+	MaxTotalSize   int               // 切割出的文件与当前文件的总大小上限（MB），0 表示不限制
+	OnRotate       func(RotateEvent) // 切割后的回调，在后台协程中调用
+
+	defaultBackups bool // MaxBackups 为 WithFile 的默认值，按时间切割时不生效
 
 	// OTLP 配置
 	Endpoint string
+	Protocol OTLPProtocol // 传输协议，默认 gRPC
 	Insecure bool
 	Headers  map[string]string
-	Timeout  time.Duration
+	Timeout  time.Duration // 单次导出的超时时间
+
+	// OTLP 发送配置
+	QueueSize     int            // 内存队列容量，队列满时丢弃，默认 10000
+	BatchSize     int            // 每批发送的条数，默认 100
+	BatchInterval time.Duration  // 批次发送间隔，默认 5s
+	RetryInitial  time.Duration  // 导出失败后的首次重试间隔，默认 1s
+	RetryMax      time.Duration  // 最大重试间隔，默认 1m
+	SpoolDir      string         // 磁盘 spool 目录，为空时失败的批次只保存在内存中
+	SpoolMaxBytes int64          // 磁盘 spool 的最大字节数，默认 100MB
+	Metric        MetricRecorder // 导出指标记录器
+
+	// OpenTelemetry 桥接配置
+	LoggerProvider otellog.LoggerProvider // 为 nil 时使用全局 LoggerProvider
+
+	// 自定义 core 配置
+	Core zapcore.Core
+
+	// 输出级别配置（所有输出通用）
+	Level       *Level                // 输出的最低级别，nil 表示只受 logger 级别控制
+	MaxLevel    *Level                // 输出的最高级别，nil 表示不限制
+	Format      Format                // 输出格式，为空时使用全局格式（OTLP 输出忽略）
+	FieldFilter func(key string) bool // 字段过滤，返回 false 的字段不会写入该输出
+	RateLimit   float64               // 每秒最多写入的日志条数，0 表示不限流
+	RateBurst   int                   // 限流允许的突发条数
 }
 
 // newOptions 创建默认配置
 func newOptions(opts ...Option) *options {
 	o := &options{
-		level:       InfoLevel,
-		format:      ConsoleFormat,
-		outputs:     []Output{{Type: StdoutOutput}},
-		enableTrace: false,
-		development: false,
-		caller:      true,
-		stacktrace:  false,
+		level:          InfoLevel,
+		format:         ConsoleFormat,
+		outputs:        []Output{{Type: StdoutOutput}},
+		enableTrace:    false,
+		development:    false,
+		caller:         true,
+		stacktrace:     false,
+		moduleLevels:   make(map[string]Level),
+		implicitStdout: true,
 	}
 
 	for _, opt := range opts {
@@ -86,17 +153,96 @@ func WithFormat(format Format) Option {
 	}
 }
 
+// WithGCPProject 设置 GCPFormat 输出中 trace 字段使用的 Google Cloud 项目 ID
+// 未设置时使用环境变量 GOOGLE_CLOUD_PROJECT
+func WithGCPProject(project string) Option {
+	return func(o *options) {
+		o.gcpProject = project
+	}
+}
+
 // WithStdout 添加标准输出
-func WithStdout() Option {
+// 显式调用时替换默认的 stdout 输出，可通过 OutputOption 设置独立的级别、格式和字段过滤
+func WithStdout(opts ...OutputOption) Option {
 	return func(o *options) {
+		if o.implicitStdout {
+			o.outputs = o.outputs[1:]
+			o.implicitStdout = false
+		}
+
+		var cfg OutputConfig
+		for _, opt := range opts {
+			opt(&cfg)
+		}
+
 		o.outputs = append(o.outputs, Output{
-			Type: StdoutOutput,
+			Type:   StdoutOutput,
+			Config: cfg,
 		})
 	}
 }
 
+// OutputOption 输出选项，适用于 WithStdout、WithFile、WithOTLP、WithOTelBridge
+type OutputOption func(*OutputConfig)
+
+// WithOutputLevel 设置输出的最低级别
+// 该级别叠加在 logger 级别之上：记录需同时满足 logger 级别和输出级别才会写入
+func WithOutputLevel(level Level) OutputOption {
+	return func(c *OutputConfig) {
+		c.Level = &level
+	}
+}
+
+// WithMinLevel 设置输出的最低级别，与 WithOutputLevel 相同
+func WithMinLevel(level Level) OutputOption {
+	return WithOutputLevel(level)
+}
+
+// WithMaxLevel 设置输出的最高级别，高于该级别的日志不写入该输出
+// 与 WithMinLevel 组合可以按级别区间分流，如主日志只记录 Info 及以下，错误日志单独写入 WithErrorFile
+func WithMaxLevel(level Level) OutputOption {
+	return func(c *OutputConfig) {
+		c.MaxLevel = &level
+	}
+}
+
+// WithOutputFormat 设置输出格式，覆盖全局格式
+func WithOutputFormat(format Format) OutputOption {
+	return func(c *OutputConfig) {
+		c.Format = format
+	}
+}
+
+// WithOutputFieldFilter 设置字段过滤函数，返回 false 的字段不会写入该输出
+func WithOutputFieldFilter(filter func(key string) bool) OutputOption {
+	return func(c *OutputConfig) {
+		c.FieldFilter = filter
+	}
+}
+
+// WithOutputOmitFields 指定不写入该输出的字段
+func WithOutputOmitFields(keys ...string) OutputOption {
+	omit := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		omit[key] = struct{}{}
+	}
+	return WithOutputFieldFilter(func(key string) bool {
+		_, ok := omit[key]
+		return !ok
+	})
+}
+
+// WithOutputRateLimit 使用令牌桶限制输出的写入速率，超出的日志被丢弃并计入丢弃统计
+// perSecond 为每秒允许的条数，burst 为允许的突发条数；Fatal 日志不受限制
+func WithOutputRateLimit(perSecond float64, burst int) OutputOption {
+	return func(c *OutputConfig) {
+		c.RateLimit = perSecond
+		c.RateBurst = burst
+	}
+}
+
 // FileOption 文件选项
-type FileOption func(*OutputConfig)
+type FileOption = OutputOption
 
 // WithFileMaxSize 设置文件最大大小（MB）
 func WithFileMaxSize(size int) FileOption {
@@ -113,9 +259,11 @@ func WithFileMaxAge(days int) FileOption {
 }
 
 // WithFileMaxBackups 设置最大备份文件数量
+// 按时间切割时默认不限制数量（只按 MaxAge 清理），需要同时限制数量时显式设置
 func WithFileMaxBackups(count int) FileOption {
 	return func(c *OutputConfig) {
 		c.MaxBackups = count
+		c.defaultBackups = false
 	}
 }
 
@@ -126,15 +274,42 @@ func WithFileCompress() FileOption {
 	}
 }
 
+// WithFileRotateInterval 按时间周期切割文件（如 time.Hour、24*time.Hour），一天以内的周期按本地零点对齐
+// 文件路径包含占位符时默认按最细的占位符推断周期，如 app-%Y-%m-%d.log 每天切割
+func WithFileRotateInterval(interval time.Duration) FileOption {
+	return func(c *OutputConfig) {
+		c.RotateInterval = interval
+	}
+}
+
+// WithFileMaxTotalSize 设置日志文件占用的总磁盘大小上限（MB），超出时删除最旧的文件
+func WithFileMaxTotalSize(mb int) FileOption {
+	return func(c *OutputConfig) {
+		c.MaxTotalSize = mb
+	}
+}
+
+// WithFileOnRotate 设置切割后的回调，可用于通知日志采集或上传归档
+// 回调在独立的后台协程中依次执行，旧文件已完成压缩和清理；回调积压超过 16 个事件时丢弃新的事件，不阻塞日志写入
+func WithFileOnRotate(fn func(RotateEvent)) FileOption {
+	return func(c *OutputConfig) {
+		c.OnRotate = fn
+	}
+}
+
 // WithFile 添加文件输出
+// 文件名可以包含 %Y %m %d %H %M %S 占位符，如 /var/log/app-%Y-%m-%d.log 每天生成一个文件；
+// 使用占位符、按时间切割、总大小限制或切割回调时使用内置的切割器，否则使用 lumberjack 按大小切割
 func WithFile(path string, opts ...FileOption) Option {
 	return func(o *options) {
 		cfg := OutputConfig{
 			FilePath:   path,
 			MaxSize:    100, // 默认 100MB
 			MaxAge:     7,   // 默认保留 7 天
-			MaxBackups: 3,   // 默认保留 3 个备份
+			MaxBackups: 3,   // 默认保留 3 个备份，按时间切割时不生效
 			Compress:   false,
+
+			defaultBackups: true,
 		}
 
 		for _, opt := range opts {
@@ -148,8 +323,17 @@ func WithFile(path string, opts ...FileOption) Option {
 	}
 }
 
+// WithErrorFile 添加错误日志文件输出，默认只写入 Warn 及以上级别的日志
+// 与主日志文件同时使用，便于单独检索错误；可通过 WithMinLevel 调整级别，其他选项与 WithFile 相同
+//
+//	logger.WithFile("/var/log/app.log"),
+//	logger.WithErrorFile("/var/log/error.log"),
+func WithErrorFile(path string, opts ...FileOption) Option {
+	return WithFile(path, append([]FileOption{WithMinLevel(WarnLevel)}, opts...)...)
+}
+
 // OTLPOption OTLP 选项
-type OTLPOption func(*OutputConfig)
+type OTLPOption = OutputOption
 
 // WithOTLPInsecure 使用不安全连接
 func WithOTLPInsecure() OTLPOption {
@@ -158,6 +342,14 @@ func WithOTLPInsecure() OTLPOption {
 	}
 }
 
+// WithOTLPProtocol 设置传输协议，默认 OTLPProtocolGRPC
+// 使用 HTTP 协议时 endpoint 可以是 host:port 或完整 URL，未指定路径时使用 /v1/logs
+func WithOTLPProtocol(protocol OTLPProtocol) OTLPOption {
+	return func(c *OutputConfig) {
+		c.Protocol = protocol
+	}
+}
+
 // WithOTLPHeaders 设置自定义 headers
 func WithOTLPHeaders(headers map[string]string) OTLPOption {
 	return func(c *OutputConfig) {
@@ -165,13 +357,52 @@ func WithOTLPHeaders(headers map[string]string) OTLPOption {
 	}
 }
 
-// WithOTLPTimeout 设置连接超时
+// WithOTLPTimeout 设置单次导出的超时时间
 func WithOTLPTimeout(timeout time.Duration) OTLPOption {
 	return func(c *OutputConfig) {
 		c.Timeout = timeout
 	}
 }
 
+// WithOTLPQueueSize 设置内存队列容量，队列满时新日志被丢弃，不会阻塞业务代码
+func WithOTLPQueueSize(size int) OTLPOption {
+	return func(c *OutputConfig) {
+		c.QueueSize = size
+	}
+}
+
+// WithOTLPBatch 设置每批发送的条数和发送间隔
+func WithOTLPBatch(size int, interval time.Duration) OTLPOption {
+	return func(c *OutputConfig) {
+		c.BatchSize = size
+		c.BatchInterval = interval
+	}
+}
+
+// WithOTLPRetry 设置导出失败后的重试间隔，按指数退避从 initial 增长到 max
+func WithOTLPRetry(initial, max time.Duration) OTLPOption {
+	return func(c *OutputConfig) {
+		c.RetryInitial = initial
+		c.RetryMax = max
+	}
+}
+
+// WithOTLPSpool 启用磁盘 spool，导出失败的日志写入 dir，采集端恢复后按顺序重放
+// maxBytes 为 spool 的最大字节数，超出时删除最旧的日志，<=0 时默认 100MB
+func WithOTLPSpool(dir string, maxBytes int64) OTLPOption {
+	return func(c *OutputConfig) {
+		c.SpoolDir = dir
+		c.SpoolMaxBytes = maxBytes
+	}
+}
+
+// WithOTLPMetric 设置导出指标记录器，每次导出（含重放）后回调
+func WithOTLPMetric(recorder MetricRecorder) OTLPOption {
+	return func(c *OutputConfig) {
+		c.Metric = recorder
+	}
+}
+
 // WithOTLP 添加 OTLP 输出（用于 SigNoz 等）
 func WithOTLP(endpoint string, opts ...OTLPOption) Option {
 	return func(o *options) {
@@ -192,6 +423,46 @@ func WithOTLP(endpoint string, opts ...OTLPOption) Option {
 	}
 }
 
+// WithOTelBridge 添加 OpenTelemetry logs SDK 桥接输出
+// 日志以 OpenTelemetry 记录的形式交给 provider，severity、trace context 和 resource 由 SDK 处理，
+// 导出方式（OTLP gRPC/HTTP、stdout 等）由 provider 的 processor 和 exporter 决定。
+// provider 为 nil 时使用 global.GetLoggerProvider()；trace context 需要同时启用 WithTrace。
+func WithOTelBridge(provider otellog.LoggerProvider, opts ...OutputOption) Option {
+	return func(o *options) {
+		cfg := OutputConfig{LoggerProvider: provider}
+		for _, opt := range opts {
+			opt(&cfg)
+		}
+
+		o.outputs = append(o.outputs, Output{
+			Type:   OTelOutput,
+			Config: cfg,
+		})
+	}
+}
+
+// WithCore 添加自定义的 zapcore.Core 输出，如测试中使用的 observer core
+// 日志经过与其他输出相同的级别控制、脱敏和采样后写入 core；
+// 与 WithStdout 一样，调用后不再添加默认的 stdout 输出
+func WithCore(core zapcore.Core, opts ...OutputOption) Option {
+	return func(o *options) {
+		if o.implicitStdout {
+			o.outputs = o.outputs[1:]
+			o.implicitStdout = false
+		}
+
+		cfg := OutputConfig{Core: core}
+		for _, opt := range opts {
+			opt(&cfg)
+		}
+
+		o.outputs = append(o.outputs, Output{
+			Type:   CoreOutput,
+			Config: cfg,
+		})
+	}
+}
+
 // WithTrace 启用 trace 集成
 func WithTrace(serviceName string) Option {
 	return func(o *options) {
@@ -204,6 +475,7 @@ func WithTrace(serviceName string) Option {
 func WithDevelopment() Option {
 	return func(o *options) {
 		o.development = true
+		o.strictFields = true
 		o.level = DebugLevel
 		o.format = ConsoleFormat
 		o.stacktrace = true
@@ -230,3 +502,113 @@ func WithResourceAttributes(attrs ...attribute.KeyValue) Option {
 		o.resourceAttributes = append(o.resourceAttributes, attrs...)
 	}
 }
+
+// WithLevelFrom 将日志级别绑定到外部来源（如配置中心），来源变化时自动调整级别
+// lookup 返回级别字符串（debug/info/warn/error/fatal），返回空字符串或无法识别时保持当前级别；
+// subscribe 注册变更回调并返回取消函数，签名与 config.OnChange 一致：
+//
+//	logger.WithLevelFrom(func() string { return config.GetString("log.level") }, config.OnChange)
+func WithLevelFrom(lookup func() string, subscribe func(func()) func()) Option {
+	return func(o *options) {
+		o.levelLookup = lookup
+		o.levelSubscribe = subscribe
+	}
+}
+
+// WithModuleLevel 为匹配 pattern 的模块 logger 设置级别覆盖
+// pattern 可以是精确名称（orm.payments）、通配符（orm.*）或前缀（orm，同时作用于其子模块）
+func WithModuleLevel(pattern string, level Level) Option {
+	return func(o *options) {
+		o.moduleLevels[pattern] = level
+	}
+}
+
+// WithModuleLevels 通过字符串批量设置模块级别覆盖，如 "orm.*=warn,http=debug"
+// 格式错误时 New 返回错误
+func WithModuleLevels(spec string) Option {
+	return func(o *options) {
+		rules, err := ParseModuleLevels(spec)
+		if err != nil {
+			o.err = err
+			return
+		}
+		for pattern, level := range rules {
+			o.moduleLevels[pattern] = level
+		}
+	}
+}
+
+// WithStrictFields 启用严格字段校验（开发模式默认启用）
+// 奇数个参数、非字符串 key 等格式错误会额外输出一条 Warn 日志，包含原始日志的调用位置
+func WithStrictFields(enabled bool) Option {
+	return func(o *options) {
+		o.strictFields = enabled
+	}
+}
+
+// samplingConfig 采样配置
+type samplingConfig struct {
+	initial    int
+	thereafter int
+	tick       time.Duration
+}
+
+// WithSampling 启用日志采样：每个 tick 周期内，相同级别和消息的日志先记录 initial 条，
+// 之后每 thereafter 条记录 1 条（thereafter 为 0 时丢弃其余全部），被丢弃的日志计入丢弃统计
+func WithSampling(initial, thereafter int, tick time.Duration) Option {
+	return func(o *options) {
+		if tick <= 0 {
+			tick = time.Second
+		}
+		o.sampling = &samplingConfig{initial: initial, thereafter: thereafter, tick: tick}
+	}
+}
+
+// WithDropSummaryInterval 设置丢弃统计的输出间隔，默认 30 秒
+// 发生丢弃后，间隔结束时输出一条 Warn 级别的 "log records dropped" 汇总日志
+func WithDropSummaryInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.dropSummaryInterval = interval
+	}
+}
+
+// WithRedaction 启用字段脱敏：所有字段（包括 With 添加的字段）在编码前按规则脱敏，作用于全部输出
+// rules 为空时使用 DefaultRedactRules；值为 JSON 对象或数组的字符串字段（如请求体）会按结构逐个脱敏
+func WithRedaction(rules ...RedactRule) Option {
+	return func(o *options) {
+		o.redactor = newRedactor(rules)
+	}
+}
+
+// WithHook 添加日志 hook，默认对 Error 及以上级别的日志调用 fn，可用于错误计数、告警通知等
+// fn 在独立的协程中按顺序调用，队列满时丢弃并计入丢弃统计，不会阻塞记录日志的调用方；
+// 字段为脱敏后的值，Entry.Context 为记录日志时传入的 context
+func WithHook(fn func(Entry), opts ...HookOption) Option {
+	return func(o *options) {
+		if fn == nil {
+			return
+		}
+		cfg := &hookConfig{fn: fn, name: "hook", level: ErrorLevel, buffer: 1024}
+		for _, opt := range opts {
+			opt(cfg)
+		}
+		if cfg.buffer <= 0 {
+			cfg.buffer = 1024
+		}
+		o.hooks = append(o.hooks, cfg)
+	}
+}
+
+// WithFatalPanic Fatal 日志刷新输出后 panic（值为 FatalPanic），不调用退出回调也不退出进程，用于测试
+func WithFatalPanic() Option {
+	return func(o *options) {
+		o.fatalPanic = true
+	}
+}
+
+// WithExitTimeout 设置 Fatal 退出前执行退出回调和刷新输出各自的最长等待时间，默认 5 秒
+func WithExitTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.exitTimeout = timeout
+	}
+}
@@ -0,0 +1,247 @@
+package logger
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// otelScopeName 桥接到 OpenTelemetry 时默认的 instrumentation scope
+const otelScopeName = "github.com/Si40Code/kit/logger"
+
+// otelCore 将日志桥接到 OpenTelemetry logs SDK 的 LoggerProvider
+//
+// 级别映射为 OpenTelemetry severity，字段转换为 attributes，
+// trace_id / span_id 字段（WithTrace 启用时由 context 提取）转换为记录的 trace context，
+// resource 和导出方式由 LoggerProvider 决定。
+type otelCore struct {
+	zapcore.LevelEnabler
+	provider otellog.LoggerProvider
+	loggers  *sync.Map // logger 名 -> otellog.Logger，子 core 共享
+	fields   []zapcore.Field
+}
+
+// newOTelCore 创建桥接 core，provider 为 nil 时使用全局 LoggerProvider
+func newOTelCore(provider otellog.LoggerProvider, level zapcore.LevelEnabler) *otelCore {
+	if provider == nil {
+		provider = global.GetLoggerProvider()
+	}
+	return &otelCore{LevelEnabler: level, provider: provider, loggers: &sync.Map{}}
+}
+
+// With 实现 zapcore.Core
+func (c *otelCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field(nil), c.fields...), fields...)
+	return &clone
+}
+
+// Check 实现 zapcore.Core
+func (c *otelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	param := otellog.EnabledParameters{Severity: otelSeverity(ent.Level)}
+	if !c.logger(ent.LoggerName).Enabled(context.Background(), param) {
+		return ce
+	}
+	return ce.AddCore(ent, c)
+}
+
+// Write 实现 zapcore.Core
+func (c *otelCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	var rec otellog.Record
+	rec.SetTimestamp(ent.Time)
+	rec.SetObservedTimestamp(time.Now())
+	rec.SetSeverity(otelSeverity(ent.Level))
+	rec.SetSeverityText(ent.Level.CapitalString())
+	rec.SetBody(otellog.StringValue(ent.Message))
+
+	// trace context 通过 ctx 传给 SDK，不作为普通属性
+	ctx := context.Background()
+	if sc, ok := otelSpanContext(enc.Fields); ok {
+		ctx = trace.ContextWithSpanContext(ctx, sc)
+		delete(enc.Fields, "trace_id")
+		delete(enc.Fields, "span_id")
+	}
+
+	keys := make([]string, 0, len(enc.Fields))
+	for k := range enc.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]otellog.KeyValue, 0, len(keys)+3)
+	for _, k := range keys {
+		attrs = append(attrs, otellog.KeyValue{Key: k, Value: otelValue(enc.Fields[k])})
+	}
+	if ent.Caller.Defined {
+		attrs = append(attrs,
+			otellog.String("code.filepath", ent.Caller.File),
+			otellog.Int("code.lineno", ent.Caller.Line),
+		)
+		if ent.Caller.Function != "" {
+			attrs = append(attrs, otellog.String("code.function", ent.Caller.Function))
+		}
+	}
+	if ent.Stack != "" {
+		attrs = append(attrs, otellog.String("exception.stacktrace", ent.Stack))
+	}
+	rec.AddAttributes(attrs...)
+
+	c.logger(ent.LoggerName).Emit(ctx, rec)
+	return nil
+}
+
+// Sync 实现 zapcore.Core，导出由 LoggerProvider 的 processor 负责，
+// 需要立即导出时调用 provider 的 ForceFlush
+func (c *otelCore) Sync() error {
+	return nil
+}
+
+// logger 返回 logger 名对应的 OpenTelemetry Logger，名称为空时使用默认 scope
+func (c *otelCore) logger(name string) otellog.Logger {
+	if name == "" {
+		name = otelScopeName
+	}
+	if l, ok := c.loggers.Load(name); ok {
+		return l.(otellog.Logger)
+	}
+	l, _ := c.loggers.LoadOrStore(name, c.provider.Logger(name))
+	return l.(otellog.Logger)
+}
+
+// otelSeverity 将日志级别映射为 OpenTelemetry severity
+func otelSeverity(level zapcore.Level) otellog.Severity {
+	switch level {
+	case zapcore.DebugLevel:
+		return otellog.SeverityDebug
+	case zapcore.InfoLevel:
+		return otellog.SeverityInfo
+	case zapcore.WarnLevel:
+		return otellog.SeverityWarn
+	case zapcore.ErrorLevel:
+		return otellog.SeverityError
+	case zapcore.DPanicLevel:
+		return otellog.SeverityError2
+	case zapcore.PanicLevel:
+		return otellog.SeverityError3
+	case zapcore.FatalLevel:
+		return otellog.SeverityFatal
+	default:
+		return otellog.SeverityUndefined
+	}
+}
+
+// otelSpanContext 从 trace_id / span_id 字段还原 span context
+func otelSpanContext(fields map[string]any) (trace.SpanContext, bool) {
+	traceHex, _ := fields["trace_id"].(string)
+	spanHex, _ := fields["span_id"].(string)
+	traceID, err := trace.TraceIDFromHex(traceHex)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	spanID, err := trace.SpanIDFromHex(spanHex)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	// 字段中没有采样决策（正在记录的 span 不一定已采样，trace 字段也可能由调用方直接传入），TraceFlags 保持为 0
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}), true
+}
+
+// otelValue 将 MapObjectEncoder 产生的值转换为 OpenTelemetry 日志值
+func otelValue(v any) otellog.Value {
+	switch v := v.(type) {
+	case nil:
+		return otellog.Value{}
+	case string:
+		return otellog.StringValue(v)
+	case bool:
+		return otellog.BoolValue(v)
+	case int:
+		return otellog.IntValue(v)
+	case int8:
+		return otellog.Int64Value(int64(v))
+	case int16:
+		return otellog.Int64Value(int64(v))
+	case int32:
+		return otellog.Int64Value(int64(v))
+	case int64:
+		return otellog.Int64Value(v)
+	case uint:
+		return otelUint(uint64(v))
+	case uint8:
+		return otellog.Int64Value(int64(v))
+	case uint16:
+		return otellog.Int64Value(int64(v))
+	case uint32:
+		return otellog.Int64Value(int64(v))
+	case uint64:
+		return otelUint(v)
+	case uintptr:
+		return otelUint(uint64(v))
+	case float32:
+		return otellog.Float64Value(float64(v))
+	case float64:
+		return otellog.Float64Value(v)
+	case complex64, complex128:
+		return otellog.StringValue(fmt.Sprint(v))
+	case []byte:
+		return otellog.StringValue(base64.StdEncoding.EncodeToString(v))
+	case time.Time:
+		return otellog.StringValue(v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return otellog.StringValue(v.String())
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		kvs := make([]otellog.KeyValue, 0, len(keys))
+		for _, k := range keys {
+			kvs = append(kvs, otellog.KeyValue{Key: k, Value: otelValue(v[k])})
+		}
+		return otellog.MapValue(kvs...)
+	case []any:
+		vals := make([]otellog.Value, 0, len(v))
+		for _, item := range v {
+			vals = append(vals, otelValue(item))
+		}
+		return otellog.SliceValue(vals...)
+	case error:
+		return otellog.StringValue(v.Error())
+	case fmt.Stringer:
+		return otellog.StringValue(v.String())
+	default:
+		return otellog.StringValue(fmt.Sprintf("%+v", v))
+	}
+}
+
+// otelUint 转换无符号整数，超出 int64 范围时使用字符串
+func otelUint(v uint64) otellog.Value {
+	if v > math.MaxInt64 {
+		return otellog.StringValue(fmt.Sprint(v))
+	}
+	return otellog.Int64Value(int64(v))
+}
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// recordingExporter 保存 LoggerProvider 导出的记录
+type recordingExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *recordingExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error   { return nil }
+func (e *recordingExporter) ForceFlush(context.Context) error { return nil }
+
+// attrs 返回记录的属性
+func attrs(r sdklog.Record) map[string]otellog.Value {
+	m := map[string]otellog.Value{}
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		m[kv.Key] = kv.Value
+		return true
+	})
+	return m
+}
+
+func TestOTelBridge(t *testing.T) {
+	exp := &recordingExporter{}
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewSimpleProcessor(exp)),
+		sdklog.WithResource(resource.NewSchemaless(attribute.String("service.name", "orders"))),
+	)
+	defer provider.Shutdown(context.Background())
+
+	l, err := New(
+		WithStdout(WithOutputLevel(FatalLevel)),
+		WithTrace("orders"),
+		WithOTelBridge(provider, WithOutputLevel(InfoLevel)),
+		WithLevel(DebugLevel),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tp := sdktrace.NewTracerProvider()
+	defer tp.Shutdown(context.Background())
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+
+	l.Debug(ctx, "filtered by output level")
+	l.Named("db").Warn(ctx, "slow query", "rows", 42, "user", map[string]any{"id": "u1"})
+
+	exp.mu.Lock()
+	defer exp.mu.Unlock()
+	if len(exp.records) != 1 {
+		t.Fatalf("exported %d records, want 1", len(exp.records))
+	}
+	r := exp.records[0]
+
+	if r.Severity() != otellog.SeverityWarn || r.SeverityText() != "WARN" {
+		t.Errorf("severity = %v %q, want WARN", r.Severity(), r.SeverityText())
+	}
+	if r.Body().AsString() != "slow query" {
+		t.Errorf("body = %v, want slow query", r.Body())
+	}
+	if r.TraceID() != span.SpanContext().TraceID() || r.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("trace context = %s/%s, want %s/%s", r.TraceID(), r.SpanID(),
+			span.SpanContext().TraceID(), span.SpanContext().SpanID())
+	}
+	// 日志字段不包含采样决策，不应声称已采样
+	if r.TraceFlags() != 0 {
+		t.Errorf("trace flags = %v, want 0", r.TraceFlags())
+	}
+	if r.InstrumentationScope().Name != "db" {
+		t.Errorf("scope = %q, want db", r.InstrumentationScope().Name)
+	}
+	if v, ok := r.Resource().Set().Value("service.name"); !ok || v.AsString() != "orders" {
+		t.Errorf("resource service.name = %v, want orders", v)
+	}
+
+	a := attrs(r)
+	if a["rows"].AsInt64() != 42 {
+		t.Errorf("rows = %v, want 42", a["rows"])
+	}
+	if user := a["user"].AsMap(); len(user) != 1 || user[0].Key != "id" || user[0].Value.AsString() != "u1" {
+		t.Errorf("user = %v, want map id=u1", a["user"])
+	}
+	if _, ok := a["trace_id"]; ok {
+		t.Error("trace_id should be carried as trace context, not as an attribute")
+	}
+	if a["code.filepath"].AsString() == "" || a["code.lineno"].AsInt64() == 0 {
+		t.Errorf("caller attributes missing: %v", a)
+	}
+}
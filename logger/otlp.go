@@ -0,0 +1,481 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	collpb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	cv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	lpb "go.opentelemetry.io/proto/otlp/logs/v1"
+	rv1 "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpRecordSeparator zap_otlp_encoder 输出中 logger 名与 protobuf 记录之间的分隔符
+const otlpRecordSeparator = "#SIGNOZ#"
+
+// OTLPStats OTLP 输出的运行状态
+type OTLPStats struct {
+	Endpoint      string
+	Connected     bool      // 最近一次导出是否成功
+	QueueLength   int       // 内存队列中等待发送的条数
+	Retrying      int       // 内存重试队列中的条数（未启用 spool 时）
+	Spooled       int64     // 磁盘 spool 中的条数
+	Sent          uint64    // 累计发送成功的条数
+	Dropped       uint64    // 累计丢弃的条数（队列满、重试队列满或 spool 超出容量）
+	ExportErrors  uint64    // 累计导出失败次数
+	LastError     string    // 最近一次导出错误
+	LastErrorTime time.Time // 最近一次导出错误的时间
+}
+
+// otlpSyncer 非阻塞的 OTLP 日志导出器
+//
+// Write 只把记录放入有界队列，不会阻塞业务代码；后台协程按批次导出。
+// 导出失败后进入退避状态，期间的批次写入磁盘 spool（未启用时放入有界的内存重试队列），
+// 按指数退避间隔探测采集端，恢复后按顺序重放。
+type otlpSyncer struct {
+	cfg       OutputConfig
+	exporter  otlpExporter
+	res       *rv1.Resource
+	resSchema string
+
+	queue   chan []byte
+	flushCh chan chan struct{}
+	closeCh chan struct{}
+	doneCh  chan struct{}
+	once    sync.Once
+
+	// 以下字段只由后台协程访问
+	batch       [][]byte
+	retry       [][][]byte // 内存重试队列，按批次保存
+	retryCount  int
+	down        bool
+	backoff     time.Duration
+	nextAttempt time.Time
+	spool       *otlpSpool
+
+	// 统计
+	sent         atomic.Uint64
+	dropped      atomic.Uint64
+	exportErrors atomic.Uint64
+	retrying     atomic.Int64
+	connected    atomic.Bool
+	mu           sync.Mutex
+	lastErr      string
+	lastErrTime  time.Time
+}
+
+// newOTLPSyncer 创建导出器，连接在首次导出时建立，不会阻塞启动
+func newOTLPSyncer(cfg OutputConfig, res *resource.Resource, schema string) (*otlpSyncer, error) {
+	exporter, err := newOTLPExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 10000
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.BatchInterval <= 0 {
+		cfg.BatchInterval = 5 * time.Second
+	}
+	if cfg.RetryInitial <= 0 {
+		cfg.RetryInitial = time.Second
+	}
+	if cfg.RetryMax <= 0 {
+		cfg.RetryMax = time.Minute
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	s := &otlpSyncer{
+		cfg:       cfg,
+		exporter:  exporter,
+		resSchema: schema,
+		queue:     make(chan []byte, cfg.QueueSize),
+		flushCh:   make(chan chan struct{}),
+		closeCh:   make(chan struct{}),
+		doneCh:    make(chan struct{}),
+		backoff:   cfg.RetryInitial,
+	}
+	s.connected.Store(true)
+
+	if res != nil {
+		iter := res.Iter()
+		attrs := make([]*cv1.KeyValue, 0, iter.Len())
+		for iter.Next() {
+			attr := iter.Attribute()
+			attrs = append(attrs, &cv1.KeyValue{
+				Key:   string(attr.Key),
+				Value: &cv1.AnyValue{Value: &cv1.AnyValue_StringValue{StringValue: attr.Value.Emit()}},
+			})
+		}
+		s.res = &rv1.Resource{Attributes: attrs}
+	}
+
+	if cfg.SpoolDir != "" {
+		spool, err := newOTLPSpool(cfg.SpoolDir, cfg.SpoolMaxBytes)
+		if err != nil {
+			exporter.close()
+			return nil, err
+		}
+		s.spool = spool
+	}
+
+	go s.run()
+	return s, nil
+}
+
+// Write 实现 zapcore.WriteSyncer，队列满时丢弃并计数，不阻塞调用方
+func (s *otlpSyncer) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+
+	select {
+	case s.queue <- data:
+	default:
+		s.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Sync 实现 zapcore.WriteSyncer，尝试导出已排队的记录，最多等待一个导出超时时间
+func (s *otlpSyncer) Sync() error {
+	timer := time.NewTimer(s.cfg.Timeout)
+	defer timer.Stop()
+
+	// 后台协程正在导出或重试时可能长时间不接收刷新请求，发送同样受超时限制
+	done := make(chan struct{})
+	select {
+	case s.flushCh <- done:
+	case <-s.doneCh:
+		return nil
+	case <-timer.C:
+		return nil
+	}
+
+	select {
+	case <-done:
+	case <-timer.C:
+	}
+	return nil
+}
+
+// Close 导出剩余记录并关闭连接，导出失败的记录写入 spool 或丢弃
+func (s *otlpSyncer) Close() error {
+	s.once.Do(func() {
+		close(s.closeCh)
+		<-s.doneCh
+	})
+	return s.exporter.close()
+}
+
+// Stats 返回运行状态
+func (s *otlpSyncer) Stats() OTLPStats {
+	st := OTLPStats{
+		Endpoint:     s.cfg.Endpoint,
+		Connected:    s.connected.Load(),
+		QueueLength:  len(s.queue),
+		Retrying:     int(s.retrying.Load()),
+		Sent:         s.sent.Load(),
+		Dropped:      s.dropped.Load(),
+		ExportErrors: s.exportErrors.Load(),
+	}
+	if s.spool != nil {
+		st.Spooled = s.spool.records.Load()
+	}
+	s.mu.Lock()
+	st.LastError, st.LastErrorTime = s.lastErr, s.lastErrTime
+	s.mu.Unlock()
+	return st
+}
+
+// run 后台协程：收集批次、导出、重试与重放
+func (s *otlpSyncer) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(s.cfg.BatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closeCh:
+			s.drain()
+			s.exportBatch()
+			s.parkRetryToSpool()
+			return
+
+		case data := <-s.queue:
+			s.batch = append(s.batch, data)
+			if len(s.batch) >= s.cfg.BatchSize {
+				s.exportBatch()
+			}
+
+		case <-ticker.C:
+			s.exportBatch()
+
+		case done := <-s.flushCh:
+			s.drain()
+			s.exportBatch()
+			close(done)
+		}
+	}
+}
+
+// drain 将队列中已有的记录全部放入当前批次
+func (s *otlpSyncer) drain() {
+	for {
+		select {
+		case data := <-s.queue:
+			s.batch = append(s.batch, data)
+		default:
+			return
+		}
+	}
+}
+
+// exportBatch 先重放暂存的批次，再导出当前批次
+// 处于退避期间、仍有未重放的批次或导出失败时，当前批次排在暂存批次之后，保证记录顺序
+func (s *otlpSyncer) exportBatch() {
+	s.replay()
+	for len(s.batch) > 0 {
+		n := len(s.batch)
+		if n > s.cfg.BatchSize {
+			n = s.cfg.BatchSize
+		}
+		batch := s.batch[:n:n]
+		s.batch = s.batch[n:]
+
+		if s.backedOff() || s.pending() {
+			s.park(batch)
+			continue
+		}
+		if err := s.export(batch, false); err != nil {
+			s.park(batch)
+		}
+	}
+	s.batch = nil
+}
+
+// backedOff 是否处于导出失败后的退避期间
+func (s *otlpSyncer) backedOff() bool {
+	return s.down && time.Now().Before(s.nextAttempt)
+}
+
+// pending 内存重试队列或磁盘 spool 中是否还有未重放的批次
+func (s *otlpSyncer) pending() bool {
+	return len(s.retry) > 0 || (s.spool != nil && s.spool.records.Load() > 0)
+}
+
+// replay 采集端可用时，按顺序重放内存重试队列和磁盘 spool
+func (s *otlpSyncer) replay() {
+	if s.backedOff() || !s.pending() {
+		return
+	}
+
+	for len(s.retry) > 0 {
+		batch := s.retry[0]
+		if err := s.export(batch, true); err != nil {
+			return
+		}
+		s.retry = s.retry[1:]
+		s.retryCount -= len(batch)
+		s.retrying.Store(int64(s.retryCount))
+	}
+
+	if s.spool == nil {
+		return
+	}
+	files, err := s.spool.files()
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		batch, err := s.spool.read(f)
+		if err != nil && len(batch) == 0 {
+			// 损坏的文件无法重放，直接删除
+			s.spool.remove(f)
+			s.dropped.Add(uint64(f.records))
+			continue
+		}
+		if err := s.export(batch, true); err != nil {
+			return
+		}
+		s.spool.remove(f)
+	}
+}
+
+// park 暂存导出失败的批次：优先写入 spool，否则放入有界的内存重试队列
+func (s *otlpSyncer) park(batch [][]byte) {
+	if s.spool != nil {
+		evicted, err := s.spool.save(batch)
+		if err == nil {
+			s.dropped.Add(uint64(evicted))
+			return
+		}
+		internalLogf("failed to spool OTLP records: %v", err)
+	}
+
+	if s.retryCount+len(batch) > s.cfg.QueueSize {
+		s.dropped.Add(uint64(len(batch)))
+		return
+	}
+	s.retry = append(s.retry, batch)
+	s.retryCount += len(batch)
+	s.retrying.Store(int64(s.retryCount))
+}
+
+// parkRetryToSpool 关闭时将内存重试队列写入 spool，便于下次启动后重放
+func (s *otlpSyncer) parkRetryToSpool() {
+	if s.spool == nil {
+		if s.retryCount > 0 {
+			s.dropped.Add(uint64(s.retryCount))
+		}
+		return
+	}
+	for _, batch := range s.retry {
+		if _, err := s.spool.save(batch); err != nil {
+			s.dropped.Add(uint64(len(batch)))
+		}
+	}
+	s.retry, s.retryCount = nil, 0
+	s.retrying.Store(0)
+}
+
+// export 发送一个批次并更新退避状态，被采集端拒绝的批次计为丢弃，不再重试
+func (s *otlpSyncer) export(batch [][]byte, replay bool) error {
+	start := time.Now()
+	err := s.send(batch)
+	duration := time.Since(start)
+
+	var permanent *otlpPermanentError
+	switch {
+	case err == nil:
+		s.down = false
+		s.backoff = s.cfg.RetryInitial
+		s.connected.Store(true)
+		s.sent.Add(uint64(len(batch)))
+	case errors.As(err, &permanent):
+		// 采集端可用但拒绝了请求，重试也不会成功，直接丢弃
+		s.down = false
+		s.connected.Store(true)
+		s.dropped.Add(uint64(len(batch)))
+		s.exportErrors.Add(1)
+		s.mu.Lock()
+		s.lastErr, s.lastErrTime = err.Error(), time.Now()
+		s.mu.Unlock()
+	default:
+		// 指数退避：下一次探测前的批次直接暂存
+		s.down = true
+		s.nextAttempt = time.Now().Add(s.backoff)
+		s.backoff *= 2
+		if s.backoff > s.cfg.RetryMax {
+			s.backoff = s.cfg.RetryMax
+		}
+		s.connected.Store(false)
+		s.exportErrors.Add(1)
+		s.mu.Lock()
+		s.lastErr, s.lastErrTime = err.Error(), time.Now()
+		s.mu.Unlock()
+	}
+
+	if s.cfg.Metric != nil {
+		data := MetricData{
+			Endpoint:    s.cfg.Endpoint,
+			Records:     len(batch),
+			Replay:      replay,
+			Success:     err == nil,
+			Duration:    duration,
+			Error:       err,
+			QueueLength: len(s.queue),
+			Dropped:     s.dropped.Load(),
+		}
+		if s.spool != nil {
+			data.Spooled = uint64(s.spool.records.Load())
+		}
+		s.cfg.Metric.RecordExport(data)
+	}
+	if permanent != nil {
+		return nil
+	}
+	return err
+}
+
+// send 将批次编码为 OTLP 请求并发送
+func (s *otlpSyncer) send(batch [][]byte) error {
+	scopes := map[string][]*lpb.LogRecord{}
+	var order []string
+	for _, v := range batch {
+		name, data, ok := strings.Cut(string(v), otlpRecordSeparator)
+		if !ok {
+			continue
+		}
+		r := &lpb.LogRecord{}
+		if err := proto.Unmarshal([]byte(data), r); err != nil {
+			continue
+		}
+		if _, exists := scopes[name]; !exists {
+			order = append(order, name)
+		}
+		scopes[name] = append(scopes[name], r)
+	}
+	if len(order) == 0 {
+		return nil
+	}
+
+	rl := &lpb.ResourceLogs{}
+	for _, name := range order {
+		sl := &lpb.ScopeLogs{LogRecords: scopes[name]}
+		if name != "" {
+			sl.SchemaUrl, sl.Scope = s.resSchema, &cv1.InstrumentationScope{Name: "logger", Version: name}
+		}
+		rl.ScopeLogs = append(rl.ScopeLogs, sl)
+	}
+	if s.res != nil {
+		rl.SchemaUrl, rl.Resource = s.resSchema, s.res
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Timeout)
+	defer cancel()
+
+	rejected, err := s.exporter.export(ctx, &collpb.ExportLogsServiceRequest{ResourceLogs: []*lpb.ResourceLogs{rl}})
+	if rejected > 0 {
+		// 被采集端拒绝的记录重试也不会成功，计为丢弃
+		s.dropped.Add(uint64(rejected))
+	}
+	return err
+}
+
+// otlpStatser 提供 OTLP 输出运行状态的 logger
+type otlpStatser interface {
+	OTLPStats() []OTLPStats
+}
+
+// OTLPOutputStats 返回 logger 中所有 OTLP 输出的运行状态，可用于健康检查或暴露为指标
+// 不支持的 logger 实现返回 nil
+func OTLPOutputStats(l Logger) []OTLPStats {
+	if s, ok := l.(otlpStatser); ok {
+		return s.OTLPStats()
+	}
+	return nil
+}
+
+// OTLPStats 返回所有 OTLP 输出的运行状态
+func (l *zapLogger) OTLPStats() []OTLPStats {
+	if len(l.otlp) == 0 {
+		return nil
+	}
+	stats := make([]OTLPStats, len(l.otlp))
+	for i, s := range l.otlp {
+		stats[i] = s.Stats()
+	}
+	return stats
+}
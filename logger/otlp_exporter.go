@@ -0,0 +1,278 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	collpb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// OTLPProtocol OTLP 传输协议
+type OTLPProtocol string
+
+const (
+	OTLPProtocolGRPC         OTLPProtocol = "grpc"
+	OTLPProtocolHTTPProtobuf OTLPProtocol = "http/protobuf"
+	OTLPProtocolHTTPJSON     OTLPProtocol = "http/json"
+)
+
+// otlpLogsPath OTLP/HTTP 日志接口的默认路径
+const otlpLogsPath = "/v1/logs"
+
+// otlpExporter 发送 OTLP 日志请求，由 otlpSyncer 负责排队、重试和 spool
+// export 返回被采集端拒绝（部分成功）的记录条数
+type otlpExporter interface {
+	export(ctx context.Context, req *collpb.ExportLogsServiceRequest) (rejected int64, err error)
+	close() error
+}
+
+// otlpPermanentError 重试也不会成功的导出错误（如请求格式错误），对应批次直接丢弃
+type otlpPermanentError struct {
+	err error
+}
+
+func (e *otlpPermanentError) Error() string { return e.err.Error() }
+
+func (e *otlpPermanentError) Unwrap() error { return e.err }
+
+// newOTLPExporter 按协议创建导出器
+func newOTLPExporter(cfg OutputConfig) (otlpExporter, error) {
+	switch cfg.Protocol {
+	case "", OTLPProtocolGRPC:
+		return newGRPCExporter(cfg)
+	case OTLPProtocolHTTPProtobuf, OTLPProtocolHTTPJSON:
+		return newHTTPExporter(cfg)
+	default:
+		return nil, fmt.Errorf("unknown OTLP protocol: %s", cfg.Protocol)
+	}
+}
+
+// grpcExporter OTLP/gRPC 导出器
+type grpcExporter struct {
+	conn   *grpc.ClientConn
+	client collpb.LogsServiceClient
+	md     metadata.MD
+}
+
+// newGRPCExporter 创建 gRPC 导出器，连接在首次导出时建立
+func newGRPCExporter(cfg OutputConfig) (*grpcExporter, error) {
+	var dialOpts []grpc.DialOption
+	if cfg.Insecure {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(nil, "")))
+	}
+
+	conn, err := grpc.NewClient(cfg.Endpoint, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP client: %w", err)
+	}
+
+	md := metadata.MD{}
+	for k, v := range otlpHeaders(cfg.Headers) {
+		md.Set(k, v)
+	}
+	return &grpcExporter{conn: conn, client: collpb.NewLogsServiceClient(conn), md: md}, nil
+}
+
+func (e *grpcExporter) export(ctx context.Context, req *collpb.ExportLogsServiceRequest) (int64, error) {
+	resp, err := e.client.Export(metadata.NewOutgoingContext(ctx, e.md), req)
+	if err != nil {
+		if !grpcRetryable(status.Code(err)) {
+			return 0, &otlpPermanentError{err: err}
+		}
+		return 0, err
+	}
+	return resp.GetPartialSuccess().GetRejectedLogRecords(), nil
+}
+
+// grpcRetryable 是否为可重试的 gRPC 状态码，与 OTLP 规范一致，其余状态码（如参数错误、鉴权失败）重试也不会成功
+func grpcRetryable(code codes.Code) bool {
+	switch code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal:
+		return true
+	default:
+		return false
+	}
+}
+
+func (e *grpcExporter) close() error {
+	return e.conn.Close()
+}
+
+// httpExporter OTLP/HTTP 导出器，支持 protobuf 和 JSON 编码
+type httpExporter struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+	json    bool
+}
+
+// newHTTPExporter 创建 HTTP 导出器
+// endpoint 可以是 host:port 或完整 URL，未指定路径时使用 /v1/logs
+func newHTTPExporter(cfg OutputConfig) (*httpExporter, error) {
+	endpoint := cfg.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		scheme := "https"
+		if cfg.Insecure {
+			scheme = "http"
+		}
+		endpoint = scheme + "://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint: %w", err)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = otlpLogsPath
+	}
+
+	return &httpExporter{
+		client:  &http.Client{},
+		url:     u.String(),
+		headers: otlpHeaders(cfg.Headers),
+		json:    cfg.Protocol == OTLPProtocolHTTPJSON,
+	}, nil
+}
+
+func (e *httpExporter) export(ctx context.Context, req *collpb.ExportLogsServiceRequest) (int64, error) {
+	var body []byte
+	var err error
+	contentType := "application/x-protobuf"
+	if e.json {
+		contentType = "application/json"
+		body, err = marshalOTLPJSON(req)
+	} else {
+		body, err = proto.Marshal(req)
+	}
+	if err != nil {
+		return 0, &otlpPermanentError{err: fmt.Errorf("failed to encode OTLP request: %w", err)}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, &otlpPermanentError{err: err}
+	}
+	httpReq.Header.Set("Content-Type", contentType)
+	for k, v := range e.headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return e.rejected(resp.Header.Get("Content-Type"), respBody), nil
+	}
+	msg := respBody
+	if len(msg) > 512 {
+		msg = msg[:512]
+	}
+	err = fmt.Errorf("OTLP endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return 0, err
+	default:
+		return 0, &otlpPermanentError{err: err}
+	}
+}
+
+// rejected 解析响应中部分成功的拒绝条数，无法解析时视为全部接收
+func (e *httpExporter) rejected(contentType string, body []byte) int64 {
+	if len(body) == 0 {
+		return 0
+	}
+	resp := &collpb.ExportLogsServiceResponse{}
+	var err error
+	if strings.HasPrefix(contentType, "application/json") {
+		err = protojson.Unmarshal(body, resp)
+	} else {
+		err = proto.Unmarshal(body, resp)
+	}
+	if err != nil {
+		return 0
+	}
+	return resp.GetPartialSuccess().GetRejectedLogRecords()
+}
+
+func (e *httpExporter) close() error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// otlpHeaders 合并环境变量 OTEL_EXPORTER_OTLP_HEADERS 与配置的 headers，配置优先
+func otlpHeaders(headers map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, header := range strings.Split(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"), ",") {
+		if k, v, ok := strings.Cut(header, "="); ok {
+			merged[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	for k, v := range headers {
+		merged[k] = v
+	}
+	return merged
+}
+
+// marshalOTLPJSON 按 OTLP/JSON 规范编码请求
+// 与 protojson 的区别：枚举使用数字，traceId / spanId 使用十六进制而不是 base64
+func marshalOTLPJSON(req *collpb.ExportLogsServiceRequest) ([]byte, error) {
+	data, err := protojson.MarshalOptions{UseEnumNumbers: true}.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	for _, rl := range jsonArray(doc["resourceLogs"]) {
+		for _, sl := range jsonArray(rl["scopeLogs"]) {
+			for _, r := range jsonArray(sl["logRecords"]) {
+				for _, key := range []string{"traceId", "spanId"} {
+					if s, ok := r[key].(string); ok {
+						if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+							r[key] = hex.EncodeToString(b)
+						}
+					}
+				}
+			}
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// jsonArray 返回 JSON 数组中的对象元素
+func jsonArray(v any) []map[string]any {
+	items, _ := v.([]any)
+	objs := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(map[string]any); ok {
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
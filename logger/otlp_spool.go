@@ -0,0 +1,212 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// spoolSuffix spool 文件后缀
+const spoolSuffix = ".spool"
+
+// otlpSpool 将导出失败的日志批次持久化到磁盘，采集端恢复后按写入顺序重放
+//
+// 每个批次一个文件，文件名为 <纳秒时间戳>-<序号>-<条数>.spool，内容为若干条
+// 4 字节大端长度 + 记录内容。超过 maxBytes 时删除最旧的文件。
+// 只由 otlpSyncer 的后台协程访问，无需加锁；计数器可被并发读取。
+type otlpSpool struct {
+	dir      string
+	maxBytes int64
+	seq      uint64
+
+	records atomic.Int64 // 当前 spool 中的记录条数
+	bytes   int64
+}
+
+// spoolFile spool 文件信息
+type spoolFile struct {
+	path    string
+	size    int64
+	records int
+}
+
+// newOTLPSpool 创建 spool 目录并统计已有文件（进程重启后继续重放）
+func newOTLPSpool(dir string, maxBytes int64) (*otlpSpool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create spool dir: %w", err)
+	}
+	if maxBytes <= 0 {
+		maxBytes = 100 << 20 // 默认 100MB
+	}
+
+	s := &otlpSpool{dir: dir, maxBytes: maxBytes}
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		s.bytes += f.size
+		s.records.Add(int64(f.records))
+	}
+	return s, nil
+}
+
+// save 写入一个批次，返回因超出容量被删除的旧记录条数
+func (s *otlpSpool) save(batch [][]byte) (evicted int, err error) {
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	s.seq++
+	name := fmt.Sprintf("%d-%d-%d%s", time.Now().UnixNano(), s.seq, len(batch), spoolSuffix)
+	path := filepath.Join(s.dir, name)
+	tmp := path + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriter(f)
+	var size int64
+	var lenBuf [4]byte
+	for _, rec := range batch {
+		binary.BigEndian.PutUint32(lenBuf[:], uint32(len(rec)))
+		if _, err = w.Write(lenBuf[:]); err == nil {
+			_, err = w.Write(rec)
+		}
+		if err != nil {
+			break
+		}
+		size += int64(4 + len(rec))
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return 0, err
+	}
+
+	s.bytes += size
+	s.records.Add(int64(len(batch)))
+	return s.evict()
+}
+
+// evict 删除最旧的文件直到总大小不超过上限
+func (s *otlpSpool) evict() (int, error) {
+	if s.bytes <= s.maxBytes {
+		return 0, nil
+	}
+	files, err := s.files()
+	if err != nil {
+		return 0, err
+	}
+
+	evicted := 0
+	for _, f := range files {
+		if s.bytes <= s.maxBytes {
+			break
+		}
+		if err := s.remove(f); err != nil {
+			return evicted, err
+		}
+		evicted += f.records
+	}
+	return evicted, nil
+}
+
+// files 按写入顺序返回 spool 文件
+func (s *otlpSpool) files() ([]spoolFile, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []spoolFile
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), spoolSuffix) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, spoolFile{
+			path:    filepath.Join(s.dir, e.Name()),
+			size:    info.Size(),
+			records: spoolRecordCount(e.Name()),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return spoolOrder(files[i].path) < spoolOrder(files[j].path)
+	})
+	return files, nil
+}
+
+// read 读取 spool 文件中的记录
+func (s *otlpSpool) read(f spoolFile) ([][]byte, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var batch [][]byte
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return batch, io.ErrUnexpectedEOF
+		}
+		n := int(binary.BigEndian.Uint32(data[:4]))
+		data = data[4:]
+		if len(data) < n {
+			return batch, io.ErrUnexpectedEOF
+		}
+		batch = append(batch, data[:n])
+		data = data[n:]
+	}
+	return batch, nil
+}
+
+// remove 删除 spool 文件并更新计数
+func (s *otlpSpool) remove(f spoolFile) error {
+	if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	s.bytes -= f.size
+	s.records.Add(-int64(f.records))
+	return nil
+}
+
+// spoolOrder 从文件名中解析排序键（时间戳、序号）
+func spoolOrder(path string) string {
+	parts := strings.SplitN(filepath.Base(path), "-", 3)
+	if len(parts) < 2 {
+		return filepath.Base(path)
+	}
+	// 补齐位数，保证字符串顺序与数值顺序一致
+	return fmt.Sprintf("%020s-%020s", parts[0], parts[1])
+}
+
+// spoolRecordCount 从文件名中解析记录条数
+func spoolRecordCount(name string) int {
+	parts := strings.SplitN(strings.TrimSuffix(name, spoolSuffix), "-", 3)
+	if len(parts) != 3 {
+		return 0
+	}
+	n, _ := strconv.Atoi(parts[2])
+	return n
+}
@@ -0,0 +1,461 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	collpb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	cv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	lpb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeCollector 记录收到的日志条数和 headers
+type fakeCollector struct {
+	collpb.UnimplementedLogsServiceServer
+
+	mu      sync.Mutex
+	records []string
+	tokens  []string
+	reject  string // 包含该内容的请求返回 InvalidArgument
+}
+
+func (c *fakeCollector) Export(ctx context.Context, req *collpb.ExportLogsServiceRequest) (*collpb.ExportLogsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		c.tokens = append(c.tokens, md.Get("x-token")...)
+	}
+	if c.reject != "" {
+		for _, rl := range req.GetResourceLogs() {
+			for _, sl := range rl.GetScopeLogs() {
+				for _, r := range sl.GetLogRecords() {
+					if r.GetBody().GetStringValue() == c.reject {
+						return nil, status.Error(codes.InvalidArgument, "malformed record")
+					}
+				}
+			}
+		}
+	}
+	for _, rl := range req.GetResourceLogs() {
+		for _, sl := range rl.GetScopeLogs() {
+			for _, r := range sl.GetLogRecords() {
+				c.records = append(c.records, r.GetBody().GetStringValue())
+			}
+		}
+	}
+	return &collpb.ExportLogsServiceResponse{}, nil
+}
+
+func (c *fakeCollector) snapshot() ([]string, []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.records...), append([]string(nil), c.tokens...)
+}
+
+// startCollector 在 addr 上启动 fake collector
+func startCollector(t *testing.T, addr string) *fakeCollector {
+	t.Helper()
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	c := &fakeCollector{}
+	srv := grpc.NewServer()
+	collpb.RegisterLogsServiceServer(srv, c)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return c
+}
+
+// freeAddr 返回一个当前未被监听的本地地址
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+	return addr
+}
+
+// waitFor 轮询直到 cond 返回 true 或超时
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return cond()
+}
+
+func TestOTLPOutputSpoolsAndReplays(t *testing.T) {
+	addr := freeAddr(t)
+	dir := t.TempDir()
+
+	start := time.Now()
+	l, err := New(
+		WithStdout(WithOutputLevel(FatalLevel)),
+		WithCaller(false),
+		WithOTLP(addr,
+			WithOTLPHeaders(map[string]string{"x-token": "secret"}),
+			WithOTLPTimeout(200*time.Millisecond),
+			WithOTLPBatch(10, 20*time.Millisecond),
+			WithOTLPRetry(10*time.Millisecond, 50*time.Millisecond),
+			WithOTLPSpool(dir, 0),
+		),
+	)
+	if err != nil {
+		t.Fatalf("New with unreachable collector: %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("New blocked for %v", d)
+	}
+
+	ctx := context.Background()
+	l.Info(ctx, "first")
+	l.Info(ctx, "second")
+	l.Info(ctx, "third")
+	_ = l.Sync()
+
+	stats := OTLPOutputStats(l)
+	if len(stats) != 1 {
+		t.Fatalf("OTLPOutputStats returned %d entries, want 1", len(stats))
+	}
+	if stats[0].Spooled != 3 || stats[0].Connected || stats[0].ExportErrors == 0 {
+		t.Fatalf("stats after failed export = %+v, want 3 spooled and disconnected", stats[0])
+	}
+
+	c := startCollector(t, addr)
+	if !waitFor(t, 5*time.Second, func() bool {
+		records, _ := c.snapshot()
+		return len(records) == 3
+	}) {
+		records, _ := c.snapshot()
+		t.Fatalf("collector received %v, want 3 replayed records", records)
+	}
+
+	records, tokens := c.snapshot()
+	for i, want := range []string{"first", "second", "third"} {
+		if records[i] != want {
+			t.Errorf("record %d = %q, want %q", i, records[i], want)
+		}
+	}
+	if len(tokens) == 0 || tokens[0] != "secret" {
+		t.Errorf("x-token header = %v, want secret", tokens)
+	}
+
+	// 采集端先记下记录再回包，统计要等导出调用返回后才会更新。
+	if !waitFor(t, 5*time.Second, func() bool {
+		stats = OTLPOutputStats(l)
+		return stats[0].Spooled == 0 && stats[0].Sent == 3 && stats[0].Connected
+	}) {
+		t.Errorf("stats after replay = %+v, want 3 sent and empty spool", stats[0])
+	}
+}
+
+func TestOTLPGRPCOutputDropsRejectedBatch(t *testing.T) {
+	addr := freeAddr(t)
+	c := startCollector(t, addr)
+	c.mu.Lock()
+	c.reject = "bad"
+	c.mu.Unlock()
+
+	l, err := New(
+		WithStdout(WithOutputLevel(FatalLevel)),
+		WithOTLP(addr, WithOTLPBatch(1, time.Hour), WithOTLPSpool(t.TempDir(), 0)),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer Close(l)
+
+	// 被拒绝的批次直接丢弃，不会阻塞之后的批次
+	ctx := context.Background()
+	l.Info(ctx, "bad")
+	_ = l.Sync()
+	l.Info(ctx, "good")
+	_ = l.Sync()
+
+	if records, _ := c.snapshot(); !equalStrings(records, []string{"good"}) {
+		t.Errorf("collector received %v, want [good]", records)
+	}
+	st := OTLPOutputStats(l)[0]
+	if st.Dropped != 1 || st.Spooled != 0 || st.Sent != 1 || !st.Connected {
+		t.Errorf("stats = %+v, want 1 dropped, 1 sent and nothing spooled", st)
+	}
+}
+
+func TestOTLPOutputDropsWhenQueueFull(t *testing.T) {
+	// 不启动后台协程，模拟采集端跟不上、队列已满的情况
+	s := &otlpSyncer{cfg: OutputConfig{Endpoint: "collector:4317"}, queue: make(chan []byte, 2)}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			if _, err := s.Write([]byte("record")); err != nil {
+				t.Errorf("Write: %v", err)
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Write blocked on a full queue")
+	}
+	if st := s.Stats(); st.QueueLength != 2 || st.Dropped != 3 {
+		t.Errorf("stats = %+v, want queue 2 and 3 dropped", st)
+	}
+}
+
+func TestOTLPSyncTimesOutWhenBusy(t *testing.T) {
+	// 不启动后台协程，模拟导出或重试期间无法接收刷新请求
+	s := &otlpSyncer{
+		cfg:     OutputConfig{Endpoint: "collector:4317", Timeout: 50 * time.Millisecond},
+		flushCh: make(chan chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = s.Sync()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Sync blocked while the exporter was busy")
+	}
+}
+
+// flakyExporter 前 failures 次导出失败，之后按顺序记录收到的日志
+type flakyExporter struct {
+	failures int
+	records  []string
+}
+
+func (e *flakyExporter) export(_ context.Context, req *collpb.ExportLogsServiceRequest) (int64, error) {
+	if e.failures > 0 {
+		e.failures--
+		return 0, errors.New("collector unavailable")
+	}
+	for _, rl := range req.GetResourceLogs() {
+		for _, sl := range rl.GetScopeLogs() {
+			for _, r := range sl.GetLogRecords() {
+				e.records = append(e.records, r.GetBody().GetStringValue())
+			}
+		}
+	}
+	return 0, nil
+}
+
+func (e *flakyExporter) close() error { return nil }
+
+// otlpTestRecord 按 zap_otlp_encoder 的输出格式编码一条日志
+func otlpTestRecord(t *testing.T, body string) []byte {
+	t.Helper()
+	data, err := proto.Marshal(&lpb.LogRecord{Body: &cv1.AnyValue{Value: &cv1.AnyValue_StringValue{StringValue: body}}})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return append([]byte(otlpRecordSeparator), data...)
+}
+
+func TestOTLPReplayPreservesOrder(t *testing.T) {
+	for _, spool := range []bool{false, true} {
+		exporter := &flakyExporter{failures: 1}
+		s := &otlpSyncer{
+			cfg:      OutputConfig{QueueSize: 100, BatchSize: 10, RetryInitial: 20 * time.Millisecond, RetryMax: time.Second},
+			exporter: exporter,
+			queue:    make(chan []byte, 100),
+			backoff:  20 * time.Millisecond,
+		}
+		if spool {
+			sp, err := newOTLPSpool(t.TempDir(), 0)
+			if err != nil {
+				t.Fatalf("newOTLPSpool: %v", err)
+			}
+			s.spool = sp
+		}
+
+		// 第一批导出失败，第二批在退避期间暂存，恢复后的第三批应排在它们之后
+		for i, body := range []string{"first", "second", "third"} {
+			if i == 2 {
+				time.Sleep(30 * time.Millisecond)
+			}
+			s.batch = [][]byte{otlpTestRecord(t, body)}
+			s.exportBatch()
+		}
+
+		want := []string{"first", "second", "third"}
+		if !equalStrings(exporter.records, want) {
+			t.Errorf("spool=%v: exported %v, want %v", spool, exporter.records, want)
+		}
+		if s.pending() {
+			t.Errorf("spool=%v: batches still pending after recovery", spool)
+		}
+	}
+}
+
+func TestOTLPSpoolEvictsOldest(t *testing.T) {
+	s, err := newOTLPSpool(t.TempDir(), 40)
+	if err != nil {
+		t.Fatalf("newOTLPSpool: %v", err)
+	}
+
+	for _, rec := range []string{"aaaaaaaaaaaa", "bbbbbbbbbbbb", "cccccccccccc"} {
+		if _, err := s.save([][]byte{[]byte(rec)}); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	files, err := s.files()
+	if err != nil {
+		t.Fatalf("files: %v", err)
+	}
+	if len(files) != 2 || s.records.Load() != 2 {
+		t.Fatalf("spool has %d files / %d records, want 2", len(files), s.records.Load())
+	}
+	batch, err := s.read(files[0])
+	if err != nil || string(batch[0]) != "bbbbbbbbbbbb" {
+		t.Errorf("oldest remaining record = %q (err %v), want bbbbbbbbbbbb", batch, err)
+	}
+}
+
+// httpRequest fake HTTP collector 收到的请求
+type httpRequest struct {
+	path        string
+	contentType string
+	token       string
+	body        []byte
+}
+
+// startHTTPCollector 启动 fake OTLP/HTTP collector，按 status 返回响应
+func startHTTPCollector(t *testing.T, status int) (*httptest.Server, func() []httpRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var reqs []httpRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		reqs = append(reqs, httpRequest{r.URL.Path, r.Header.Get("Content-Type"), r.Header.Get("X-Token"), body})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []httpRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]httpRequest(nil), reqs...)
+	}
+}
+
+func TestOTLPHTTPOutput(t *testing.T) {
+	for _, protocol := range []OTLPProtocol{OTLPProtocolHTTPProtobuf, OTLPProtocolHTTPJSON} {
+		t.Run(string(protocol), func(t *testing.T) {
+			srv, requests := startHTTPCollector(t, http.StatusOK)
+
+			l, err := New(
+				WithStdout(WithOutputLevel(FatalLevel)),
+				WithOTLP(srv.URL,
+					WithOTLPProtocol(protocol),
+					WithOTLPHeaders(map[string]string{"X-Token": "secret"}),
+				),
+			)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			l.Info(context.Background(), "hello", "trace_id", "0102030405060708090a0b0c0d0e0f10", "span_id", "0102030405060708")
+			_ = l.Sync()
+
+			reqs := requests()
+			if len(reqs) != 1 {
+				t.Fatalf("collector received %d requests, want 1", len(reqs))
+			}
+			r := reqs[0]
+			if r.path != "/v1/logs" || r.token != "secret" {
+				t.Errorf("request path/token = %q/%q, want /v1/logs/secret", r.path, r.token)
+			}
+
+			req := &collpb.ExportLogsServiceRequest{}
+			if protocol == OTLPProtocolHTTPJSON {
+				if r.contentType != "application/json" {
+					t.Errorf("Content-Type = %q, want application/json", r.contentType)
+				}
+				// OTLP/JSON 要求 traceId 使用十六进制编码
+				if !strings.Contains(string(r.body), `"traceId":"0102030405060708090a0b0c0d0e0f10"`) {
+					t.Errorf("JSON body does not carry hex traceId: %s", r.body)
+				}
+				var doc map[string]any
+				if err := json.Unmarshal(r.body, &doc); err != nil {
+					t.Fatalf("invalid JSON body: %v", err)
+				}
+				// 还原为 base64 后用 protojson 解析，校验其余字段
+				body := strings.Replace(string(r.body), "0102030405060708090a0b0c0d0e0f10", "AQIDBAUGBwgJCgsMDQ4PEA==", 1)
+				body = strings.Replace(body, `"spanId":"0102030405060708"`, `"spanId":"AQIDBAUGBwg="`, 1)
+				if err := protojson.Unmarshal([]byte(body), req); err != nil {
+					t.Fatalf("protojson: %v", err)
+				}
+			} else {
+				if r.contentType != "application/x-protobuf" {
+					t.Errorf("Content-Type = %q, want application/x-protobuf", r.contentType)
+				}
+				if err := proto.Unmarshal(r.body, req); err != nil {
+					t.Fatalf("proto: %v", err)
+				}
+			}
+
+			rec := req.GetResourceLogs()[0].GetScopeLogs()[0].GetLogRecords()[0]
+			if rec.GetBody().GetStringValue() != "hello" {
+				t.Errorf("body = %v, want hello", rec.GetBody())
+			}
+			if len(rec.GetTraceId()) != 16 || rec.GetTraceId()[0] != 1 {
+				t.Errorf("trace id = %x", rec.GetTraceId())
+			}
+		})
+	}
+}
+
+func TestOTLPHTTPOutputDropsRejectedBatch(t *testing.T) {
+	srv, requests := startHTTPCollector(t, http.StatusBadRequest)
+
+	l, err := New(
+		WithStdout(WithOutputLevel(FatalLevel)),
+		WithOTLP(srv.URL, WithOTLPProtocol(OTLPProtocolHTTPProtobuf), WithOTLPSpool(t.TempDir(), 0)),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	l.Info(context.Background(), "bad")
+	_ = l.Sync()
+
+	if n := len(requests()); n != 1 {
+		t.Fatalf("collector received %d requests, want 1", n)
+	}
+	st := OTLPOutputStats(l)[0]
+	if st.Dropped != 1 || st.Spooled != 0 || !st.Connected || !strings.Contains(st.LastError, "400") {
+		t.Errorf("stats = %+v, want 1 dropped and nothing spooled", st)
+	}
+}
@@ -1,39 +1,74 @@
 package logger
 
 import (
-	"context"
 	"fmt"
 	"os"
-	"time"
 
 	zapotlpencoder "github.com/SigNoz/zap_otlp/zap_otlp_encoder"
-	zapotlpsync "github.com/SigNoz/zap_otlp/zap_otlp_sync"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+// outputRuntime 输出运行时状态，整棵 logger 树共享
+type outputRuntime struct {
+	drops    *dropCounter   // 采样和限流的丢弃统计
+	otlp     []*otlpSyncer  // OTLP 导出器，用于查询运行状态
+	rotators []*fileRotator // 内置的文件切割器，关闭 logger 时关闭
+}
+
+// close 关闭已创建的 OTLP 导出器和文件切割器，用于创建 logger 失败时释放资源
+func (rt *outputRuntime) close() {
+	for _, s := range rt.otlp {
+		s.Close()
+	}
+	for _, r := range rt.rotators {
+		r.Close()
+	}
+}
+
 // createCores 创建所有输出的 cores
-func createCores(opts *options) ([]zapcore.Core, error) {
+// 每个输出可以设置独立的级别、格式和字段过滤，未设置时使用全局配置
+func createCores(opts *options, rt *outputRuntime) ([]zapcore.Core, error) {
 	var cores []zapcore.Core
-	level := zapLevel(opts.level)
 
 	for _, output := range opts.outputs {
+		cfg := output.Config
+		format := opts.format
+		if cfg.Format != "" {
+			format = cfg.Format
+		}
+		level := outputLevel(cfg)
+
 		var core zapcore.Core
 		var err error
 
 		switch output.Type {
 		case StdoutOutput:
-			core, err = createStdoutCore(opts.format, opts.development, level)
+			core, err = createStdoutCore(format, opts, level)
 		case FileOutput:
-			core, err = createFileCore(output.Config, opts.format, opts.development, level)
+			var rotator *fileRotator
+			core, rotator, err = createFileCore(cfg, format, opts, level)
+			if rotator != nil {
+				rt.rotators = append(rt.rotators, rotator)
+			}
 		case OTLPOutput:
-			core, err = createOTLPCore(output.Config, opts.serviceName, opts.resourceAttributes, level)
+			var syncer *otlpSyncer
+			core, syncer, err = createOTLPCore(cfg, opts.serviceName, opts.resourceAttributes, level)
+			if err == nil {
+				rt.otlp = append(rt.otlp, syncer)
+			}
+		case OTelOutput:
+			core = newOTelCore(cfg.LoggerProvider, level)
+		case CoreOutput:
+			if cfg.Core == nil {
+				err = fmt.Errorf("core is nil")
+			} else {
+				core = &levelRangeCore{Core: cfg.Core, level: level}
+			}
 		default:
 			return nil, fmt.Errorf("unknown output type: %s", output.Type)
 		}
@@ -42,12 +77,25 @@ func createCores(opts *options) ([]zapcore.Core, error) {
 			return nil, fmt.Errorf("failed to create %s core: %w", output.Type, err)
 		}
 
+		if cfg.FieldFilter != nil {
+			core = &fieldFilterCore{Core: core, filter: cfg.FieldFilter}
+		}
+
+		if cfg.RateLimit > 0 {
+			core = &rateLimitCore{
+				Core:    core,
+				name:    outputName(output),
+				limiter: newTokenBucket(cfg.RateLimit, cfg.RateBurst),
+				drops:   rt.drops,
+			}
+		}
+
 		cores = append(cores, core)
 	}
 
 	if len(cores) == 0 {
 		// 如果没有配置任何输出，默认使用 stdout
-		core, err := createStdoutCore(opts.format, opts.development, level)
+		core, err := createStdoutCore(opts.format, opts, zapcore.DebugLevel)
 		if err != nil {
 			return nil, err
 		}
@@ -57,70 +105,73 @@ func createCores(opts *options) ([]zapcore.Core, error) {
 	return cores, nil
 }
 
+// outputLevel 返回输出的级别，未设置时不额外过滤（由 logger 级别控制）
+func outputLevel(cfg OutputConfig) zapcore.LevelEnabler {
+	minLevel := zapcore.DebugLevel
+	if cfg.Level != nil {
+		minLevel = zapLevel(*cfg.Level)
+	}
+	if cfg.MaxLevel == nil {
+		return minLevel
+	}
+
+	maxLevel := zapLevel(*cfg.MaxLevel)
+	return zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l >= minLevel && l <= maxLevel
+	})
+}
+
 // createStdoutCore 创建标准输出 core
-func createStdoutCore(format Format, development bool, level zapcore.Level) (zapcore.Core, error) {
-	encoder := createEncoder(format, development)
+func createStdoutCore(format Format, opts *options, level zapcore.LevelEnabler) (zapcore.Core, error) {
+	encoder := createEncoder(format, opts)
 	writer := zapcore.Lock(os.Stdout)
 	return zapcore.NewCore(encoder, writer, level), nil
 }
 
-// createFileCore 创建文件输出 core
-func createFileCore(cfg OutputConfig, format Format, development bool, level zapcore.Level) (zapcore.Core, error) {
+// createFileCore 创建文件输出 core，使用内置切割器时一并返回，由 logger 负责关闭
+func createFileCore(cfg OutputConfig, format Format, opts *options, level zapcore.LevelEnabler) (zapcore.Core, *fileRotator, error) {
 	if cfg.FilePath == "" {
-		return nil, fmt.Errorf("file path is required for file output")
+		return nil, nil, fmt.Errorf("file path is required for file output")
 	}
 
-	// 使用 lumberjack 实现日志切割
-	writer := &lumberjack.Logger{
-		Filename:   cfg.FilePath,
-		MaxSize:    cfg.MaxSize,    // MB
-		MaxAge:     cfg.MaxAge,     // days
-		MaxBackups: cfg.MaxBackups, // 保留文件数
-		Compress:   cfg.Compress,   // 是否压缩
-		LocalTime:  true,           // 使用本地时间
+	var writeSyncer zapcore.WriteSyncer
+	var rotator *fileRotator
+	if useFileRotator(cfg) {
+		// 按时间、文件名模式或总大小切割
+		var err error
+		rotator, err = newFileRotator(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		writeSyncer = rotator
+	} else {
+		// 使用 lumberjack 实现日志切割
+		writeSyncer = zapcore.AddSync(&lumberjack.Logger{
+			Filename:   cfg.FilePath,
+			MaxSize:    cfg.MaxSize,    // MB
+			MaxAge:     cfg.MaxAge,     // days
+			MaxBackups: cfg.MaxBackups, // 保留文件数
+			Compress:   cfg.Compress,   // 是否压缩
+			LocalTime:  true,           // 使用本地时间
+		})
 	}
 
-	encoder := createEncoder(format, development)
-	writeSyncer := zapcore.AddSync(writer)
+	encoder := createEncoder(format, opts)
 
-	return zapcore.NewCore(encoder, writeSyncer, level), nil
+	return zapcore.NewCore(encoder, writeSyncer, level), rotator, nil
 }
 
 // createOTLPCore 创建 OTLP 输出 core（用于 SigNoz）
-func createOTLPCore(cfg OutputConfig, serviceName string, resourceAttrs []attribute.KeyValue, level zapcore.Level) (zapcore.Core, error) {
+// 连接在首次导出时建立，采集端不可用时不会阻塞启动和业务代码
+func createOTLPCore(cfg OutputConfig, serviceName string, resourceAttrs []attribute.KeyValue, level zapcore.LevelEnabler) (zapcore.Core, *otlpSyncer, error) {
 	if cfg.Endpoint == "" {
-		return nil, fmt.Errorf("endpoint is required for OTLP output")
+		return nil, nil, fmt.Errorf("endpoint is required for OTLP output")
 	}
 
 	if serviceName == "" {
 		serviceName = "unknown-service"
 	}
 
-	// 创建 gRPC 连接选项
-	var dialOpts []grpc.DialOption
-
-	if cfg.Insecure {
-		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	} else {
-		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(nil, "")))
-	}
-
-	// 设置超时
-	timeout := cfg.Timeout
-	if timeout == 0 {
-		timeout = 5 * time.Second
-	}
-	dialOpts = append(dialOpts, grpc.WithTimeout(timeout), grpc.WithBlock())
-
-	// 建立连接
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-
-	conn, err := grpc.DialContext(ctx, cfg.Endpoint, dialOpts...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to OTLP endpoint: %w", err)
-	}
-
 	// 创建 OTLP encoder
 	encoderConfig := zapcore.EncoderConfig{
 		TimeKey:        "timestamp",
@@ -146,15 +197,70 @@ func createOTLPCore(cfg OutputConfig, serviceName string, resourceAttrs []attrib
 	resourceAttributes = append(resourceAttributes, resourceAttrs...)
 
 	// 创建 OTLP Syncer
-	otlpSyncer := zapotlpsync.NewOtlpSyncer(conn, zapotlpsync.Options{
-		BatchSize:      100,
-		BatchInterval:  5 * time.Second,
-		ResourceSchema: semconv.SchemaURL,
-		Resource: resource.NewWithAttributes(
-			semconv.SchemaURL,
-			resourceAttributes...,
-		),
-	})
+	syncer, err := newOTLPSyncer(cfg,
+		resource.NewWithAttributes(semconv.SchemaURL, resourceAttributes...), semconv.SchemaURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return zapcore.NewCore(otlpEncoder, syncer, level), syncer, nil
+}
+
+// levelRangeCore 为自定义 core 叠加输出级别范围
+type levelRangeCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+// Enabled 实现 zapcore.Core
+func (c *levelRangeCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level) && c.Core.Enabled(level)
+}
+
+// With 实现 zapcore.Core
+func (c *levelRangeCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelRangeCore{Core: c.Core.With(fields), level: c.level}
+}
 
-	return zapcore.NewCore(otlpEncoder, otlpSyncer, level), nil
+// Check 实现 zapcore.Core
+func (c *levelRangeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.level.Enabled(ent.Level) {
+		return c.Core.Check(ent, ce)
+	}
+	return ce
+}
+
+// fieldFilterCore 按字段名过滤写入单个输出的字段
+type fieldFilterCore struct {
+	zapcore.Core
+	filter func(key string) bool
+}
+
+// With 实现 zapcore.Core
+func (c *fieldFilterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &fieldFilterCore{Core: c.Core.With(c.filterFields(fields)), filter: c.filter}
+}
+
+// Check 实现 zapcore.Core
+func (c *fieldFilterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core
+func (c *fieldFilterCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.filterFields(fields))
+}
+
+// filterFields 返回通过过滤的字段
+func (c *fieldFilterCore) filterFields(fields []zapcore.Field) []zapcore.Field {
+	kept := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		if c.filter(f.Key) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
 }
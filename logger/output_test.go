@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestPerOutputLevelFormatAndFilter(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "app.json")
+	consolePath := filepath.Join(dir, "app.log")
+
+	l, err := New(
+		WithLevel(DebugLevel),
+		WithFormat(ConsoleFormat),
+		WithFile(jsonPath, WithOutputLevel(WarnLevel), WithOutputFormat(JSONFormat), WithOutputOmitFields("password")),
+		WithFile(consolePath),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	l.Debug(ctx, "debug message")
+	l.Warn(ctx, "warn message", "user", "alice", "password", "secret")
+	_ = l.Sync()
+
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line in json output, got %d: %s", len(lines), data)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("json output is not json: %v", err)
+	}
+	if entry["msg"] != "warn message" || entry["user"] != "alice" {
+		t.Fatalf("unexpected json entry: %v", entry)
+	}
+	if _, ok := entry["password"]; ok {
+		t.Fatal("omitted field written to json output")
+	}
+
+	data, err = os.ReadFile(consolePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	if !strings.Contains(text, "debug message") || !strings.Contains(text, "secret") {
+		t.Fatalf("console output should use global level and keep all fields: %s", text)
+	}
+	if strings.HasPrefix(strings.TrimSpace(text), "{") {
+		t.Fatalf("console output should use global console format: %s", text)
+	}
+}
+
+func TestWithStdoutReplacesDefault(t *testing.T) {
+	o := newOptions(WithStdout(WithOutputLevel(ErrorLevel)))
+	if len(o.outputs) != 1 || o.outputs[0].Config.Level == nil {
+		t.Fatalf("expected explicit stdout to replace the default one, got %+v", o.outputs)
+	}
+
+	o = newOptions(WithFile("app.log"))
+	if len(o.outputs) != 2 || o.outputs[0].Type != StdoutOutput {
+		t.Fatalf("expected default stdout to be kept alongside file, got %+v", o.outputs)
+	}
+}
+
+func TestWithErrorFileAndMaxLevel(t *testing.T) {
+	dir := t.TempDir()
+	appPath := filepath.Join(dir, "app.log")
+	errorPath := filepath.Join(dir, "error.log")
+
+	l, err := New(
+		WithLevel(DebugLevel),
+		WithFormat(JSONFormat),
+		WithStdout(WithOutputLevel(FatalLevel)),
+		WithFile(appPath, WithMaxLevel(InfoLevel)),
+		WithErrorFile(errorPath),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	l.Debug(ctx, "debug message")
+	l.Info(ctx, "info message")
+	l.Warn(ctx, "warn message")
+	l.Error(ctx, "error message")
+	_ = l.Sync()
+
+	for path, want := range map[string][]string{
+		appPath:   {"debug message", "info message"},
+		errorPath: {"warn message", "error message"},
+	} {
+		lines := readJSONLines(t, path)
+		if len(lines) != len(want) {
+			t.Fatalf("%s: got %d lines, want %d: %v", filepath.Base(path), len(lines), len(want), lines)
+		}
+		for i, msg := range want {
+			if lines[i]["msg"] != msg {
+				t.Errorf("%s line %d = %v, want %q", filepath.Base(path), i, lines[i]["msg"], msg)
+			}
+		}
+	}
+}
+
+func TestWithCore(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l, err := New(WithLevel(DebugLevel), WithCore(core, WithMinLevel(InfoLevel), WithMaxLevel(WarnLevel)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if o := newOptions(WithCore(core)); len(o.outputs) != 1 || o.outputs[0].Type != CoreOutput {
+		t.Fatalf("expected custom core to replace the default stdout, got %+v", o.outputs)
+	}
+
+	ctx := context.Background()
+	l.With("request_id", "r-1").Debug(ctx, "below range")
+	l.With("request_id", "r-1").Info(ctx, "in range")
+	l.Error(ctx, "above range")
+
+	entries := logs.All()
+	if len(entries) != 1 || entries[0].Message != "in range" || entries[0].ContextMap()["request_id"] != "r-1" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	if _, err := New(WithCore(nil)); err == nil {
+		t.Fatal("expected error for nil core")
+	}
+}
@@ -0,0 +1,597 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// redactedValue 完全脱敏后的值，与 httpclient、config 中的掩码保持一致
+const redactedValue = "******"
+
+// Masker 脱敏函数，输入原始值返回脱敏后的值
+type Masker func(value string) string
+
+// MaskAll 完全替换为 ******，不暴露原始长度
+func MaskAll() Masker {
+	return func(string) string { return redactedValue }
+}
+
+// MaskKeepLast 保留最后 n 个字符，其余替换为 *，如银行卡号保留后四位
+func MaskKeepLast(n int) Masker {
+	return MaskKeepFirstLast(0, n)
+}
+
+// MaskKeepFirstLast 保留前 first 个和后 last 个字符，其余替换为 *
+// 值长度不超过 first+last 时完全替换
+func MaskKeepFirstLast(first, last int) Masker {
+	return func(value string) string {
+		runes := []rune(value)
+		if first < 0 {
+			first = 0
+		}
+		if last < 0 {
+			last = 0
+		}
+		if len(runes) <= first+last {
+			return redactedValue
+		}
+		masked := make([]rune, len(runes))
+		for i, r := range runes {
+			if i < first || i >= len(runes)-last {
+				masked[i] = r
+			} else {
+				masked[i] = '*'
+			}
+		}
+		return string(masked)
+	}
+}
+
+// MaskEmail 保留邮箱用户名首字符和域名，如 a****@example.com
+// 非邮箱格式的值完全替换
+func MaskEmail() Masker {
+	return func(value string) string {
+		at := strings.LastIndexByte(value, '@')
+		if at <= 0 {
+			return redactedValue
+		}
+		_, size := utf8.DecodeRuneInString(value)
+		return value[:size] + "****" + value[at:]
+	}
+}
+
+// MaskHash 替换为 SHA-256 摘要前 12 位，相同值脱敏结果相同，便于关联排查
+func MaskHash() Masker {
+	return func(value string) string {
+		sum := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(sum[:6])
+	}
+}
+
+// RedactRule 脱敏规则，由 RedactKeys、RedactPattern 等函数创建
+//
+// key 规则匹配字段名（包括 JSON 字符串中的 key），命中时整个值被脱敏；
+// 正则规则匹配字符串值中的片段，只替换命中的部分，通过 ForKeys 限定字段名后也会检查数值字段。
+type RedactRule struct {
+	keys     []string
+	scope    []string // 正则规则生效的字段名，为空时作用于所有字符串
+	pattern  *regexp.Regexp
+	validate func(match string) bool
+	mask     Masker
+	digitEnd bool // 命中片段的前后不能紧邻数字，用于代替 \b（如 8613812345678 中 86 与号码之间没有单词边界）
+}
+
+// DefaultRedactKeys 默认的敏感字段名
+var DefaultRedactKeys = []string{
+	"password", "passwd", "secret", "token", "authorization", "cookie",
+	"apikey", "accesskey", "privatekey", "credential",
+}
+
+// RedactKeys 按字段名脱敏，mask 为 nil 时使用 MaskAll
+//
+// 匹配时忽略大小写以及 '-'、'_'、'.'，字段名包含任一关键字即命中，
+// 如 "password" 命中 db_password，"apikey" 命中 X-Api-Key。
+func RedactKeys(mask Masker, keys ...string) RedactRule {
+	normalized := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k = normalizeRedactKey(k); k != "" {
+			normalized = append(normalized, k)
+		}
+	}
+	return RedactRule{keys: normalized, mask: mask}
+}
+
+// RedactPattern 对字符串值中匹配正则的片段脱敏，mask 为 nil 时使用 MaskAll
+func RedactPattern(re *regexp.Regexp, mask Masker) RedactRule {
+	return RedactRule{pattern: re, mask: mask}
+}
+
+// ForKeys 将正则规则限定在字段名包含任一关键字的值上（匹配方式与 RedactKeys 相同），
+// 不传关键字时作用于所有字符串值。限定后数值类型的字段同样按字符串检查。
+func (r RedactRule) ForKeys(keys ...string) RedactRule {
+	r.scope = nil
+	for _, k := range keys {
+		if k = normalizeRedactKey(k); k != "" {
+			r.scope = append(r.scope, k)
+		}
+	}
+	return r
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`(?:\+?86[- ]?)?1[3-9]\d{9}`)
+	cardPattern  = regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`)
+	idPattern    = regexp.MustCompile(`\b\d{17}[\dXx]\b`)
+)
+
+// RedactEmails 脱敏邮箱地址，mask 为 nil 时使用 MaskEmail
+func RedactEmails(mask Masker) RedactRule {
+	if mask == nil {
+		mask = MaskEmail()
+	}
+	return RedactRule{pattern: emailPattern, mask: mask}
+}
+
+// RedactPhones 脱敏中国大陆手机号（可带 +86 前缀），mask 为 nil 时保留前 3 位和后 4 位
+func RedactPhones(mask Masker) RedactRule {
+	if mask == nil {
+		mask = func(value string) string {
+			digits := value[len(value)-11:]
+			return MaskKeepFirstLast(3, 4)(digits)
+		}
+	}
+	return RedactRule{pattern: phonePattern, mask: mask, digitEnd: true}
+}
+
+// DefaultCardKeys RedactCardNumbers 默认检查的字段名
+var DefaultCardKeys = []string{"card", "bankaccount", "accountno", "acctno"}
+
+// DefaultIDNumberKeys RedactIDNumbers 默认检查的字段名
+var DefaultIDNumberKeys = []string{"idno", "idcard", "idnumber", "identity", "certno"}
+
+// RedactCardNumbers 脱敏通过 Luhn 校验的 13-19 位银行卡号（可含空格或 '-'），mask 为 nil 时保留后 4 位
+//
+// 随机的 13-19 位数字约 1/10 能通过 Luhn 校验，为避免误伤订单号、trace ID 等，
+// 默认只检查字段名包含 DefaultCardKeys 的值，需要检查所有字符串时使用 .ForKeys()
+func RedactCardNumbers(mask Masker) RedactRule {
+	if mask == nil {
+		mask = MaskKeepLast(4)
+	}
+	return RedactRule{pattern: cardPattern, validate: luhnValid, mask: mask}.ForKeys(DefaultCardKeys...)
+}
+
+// RedactIDNumbers 脱敏通过校验位验证的 18 位居民身份证号，mask 为 nil 时保留前 6 位和后 4 位
+// 与 RedactCardNumbers 相同，默认只检查字段名包含 DefaultIDNumberKeys 的值
+func RedactIDNumbers(mask Masker) RedactRule {
+	if mask == nil {
+		mask = MaskKeepFirstLast(6, 4)
+	}
+	return RedactRule{pattern: idPattern, validate: idNumberValid, mask: mask}.ForKeys(DefaultIDNumberKeys...)
+}
+
+// DefaultRedactRules 默认脱敏规则：DefaultRedactKeys 中的字段完全脱敏，
+// 邮箱、手机号按各自的默认方式部分脱敏，身份证号、银行卡号只在对应字段名下部分脱敏
+func DefaultRedactRules() []RedactRule {
+	return []RedactRule{
+		RedactKeys(nil, DefaultRedactKeys...),
+		RedactEmails(nil),
+		RedactPhones(nil),
+		RedactIDNumbers(nil),
+		RedactCardNumbers(nil),
+	}
+}
+
+// redactor 按规则脱敏字段
+type redactor struct {
+	keys     []RedactRule
+	patterns []RedactRule
+}
+
+// newRedactor 创建脱敏器，rules 为空时使用 DefaultRedactRules
+func newRedactor(rules []RedactRule) *redactor {
+	if len(rules) == 0 {
+		rules = DefaultRedactRules()
+	}
+	r := &redactor{}
+	for _, rule := range rules {
+		if rule.mask == nil {
+			rule.mask = MaskAll()
+		}
+		if len(rule.keys) > 0 {
+			r.keys = append(r.keys, rule)
+		}
+		if rule.pattern != nil {
+			r.patterns = append(r.patterns, rule)
+		}
+	}
+	return r
+}
+
+// keyMasker 返回字段名命中的 key 规则的脱敏函数
+func (r *redactor) keyMasker(key string) Masker {
+	if len(r.keys) == 0 || key == "" {
+		return nil
+	}
+	key = normalizeRedactKey(key)
+	for _, rule := range r.keys {
+		if matchRedactKey(key, rule.keys) {
+			return rule.mask
+		}
+	}
+	return nil
+}
+
+// scoped 判断字段名是否命中限定了字段名的正则规则，命中时数值字段也需要检查
+func (r *redactor) scoped(key string) bool {
+	if key == "" {
+		return false
+	}
+	key = normalizeRedactKey(key)
+	for _, rule := range r.patterns {
+		if len(rule.scope) > 0 && matchRedactKey(key, rule.scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactFields 返回脱敏后的字段，没有字段需要脱敏时返回原切片
+func (r *redactor) redactFields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		nf, changed := r.redactField(f)
+		if !changed {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		out = append(out, nf)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// redactField 脱敏单个字段
+func (r *redactor) redactField(f zapcore.Field) (zapcore.Field, bool) {
+	if f.Type == zapcore.NamespaceType || f.Type == zapcore.SkipType {
+		return f, false
+	}
+	if mask := r.keyMasker(f.Key); mask != nil {
+		return zap.String(f.Key, mask(fieldString(f))), true
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		if s := r.redactString(f.Key, f.String); s != f.String {
+			return zap.String(f.Key, s), true
+		}
+	case zapcore.ByteStringType:
+		if s := r.redactString(f.Key, string(f.Interface.([]byte))); s != string(f.Interface.([]byte)) {
+			return zap.String(f.Key, s), true
+		}
+	case zapcore.StringerType:
+		if str, ok := f.Interface.(fmt.Stringer); ok {
+			v := str.String()
+			if s := r.redactString(f.Key, v); s != v {
+				return zap.String(f.Key, s), true
+			}
+		}
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Uint64Type, zapcore.Uint32Type:
+		// 银行卡号等以数值记录时，与字符串使用相同的规则
+		if r.scoped(f.Key) {
+			v := fieldString(f)
+			if s := r.redactString(f.Key, v); s != v {
+				return zap.String(f.Key, s), true
+			}
+		}
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			v := err.Error()
+			if s := r.redactString(f.Key, v); s != v {
+				return zap.NamedError(f.Key, errors.New(s)), true
+			}
+		}
+	case zapcore.ObjectMarshalerType, zapcore.InlineMarshalerType, zapcore.ArrayMarshalerType, zapcore.ReflectType:
+		// 复杂值先编码为 map / slice，再逐层脱敏
+		enc := zapcore.NewMapObjectEncoder()
+		f.AddTo(enc)
+		if f.Type == zapcore.ReflectType {
+			// 反射值可能是调用方持有的 map 或结构体，复制为 JSON 结构后再脱敏
+			v, ok := jsonCopy(f.Interface)
+			if !ok {
+				return f, false
+			}
+			enc.Fields[f.Key] = v
+		}
+		changed := false
+		for k, v := range enc.Fields {
+			nv, c := r.redactValue(k, v)
+			enc.Fields[k] = nv
+			changed = changed || c
+		}
+		if !changed {
+			return f, false
+		}
+		if f.Type == zapcore.InlineMarshalerType {
+			return zap.Inline(redactedObject(enc.Fields)), true
+		}
+		return zap.Any(f.Key, enc.Fields[f.Key]), true
+	}
+	return f, false
+}
+
+// redactValue 递归脱敏 MapObjectEncoder 或 JSON 解码得到的值
+func (r *redactor) redactValue(key string, v any) (any, bool) {
+	if mask := r.keyMasker(key); mask != nil {
+		return mask(valueString(v)), true
+	}
+	switch v := v.(type) {
+	case string:
+		if s := r.redactString(key, v); s != v {
+			return s, true
+		}
+	case json.Number, int64, int32, int, uint64, uint32, uint:
+		if r.scoped(key) {
+			str := valueString(v)
+			if s := r.redactString(key, str); s != str {
+				return s, true
+			}
+		}
+	case map[string]any:
+		changed := false
+		for k, item := range v {
+			nv, c := r.redactValue(k, item)
+			v[k] = nv
+			changed = changed || c
+		}
+		return v, changed
+	case []any:
+		changed := false
+		// 数组元素沿用所在字段的字段名，如 cards: ["..."]
+		for i, item := range v {
+			nv, c := r.redactValue(key, item)
+			v[i] = nv
+			changed = changed || c
+		}
+		return v, changed
+	}
+	return v, false
+}
+
+// redactString 脱敏字段名为 key 的字符串值：JSON 对象或数组按结构脱敏，其他字符串按正则规则替换
+func (r *redactor) redactString(key, s string) string {
+	if redacted, ok := r.redactJSON(s); ok {
+		return redacted
+	}
+	nkey := normalizeRedactKey(key)
+	for _, rule := range r.patterns {
+		if len(rule.scope) > 0 && !matchRedactKey(nkey, rule.scope) {
+			continue
+		}
+		if !rule.pattern.MatchString(s) {
+			continue
+		}
+		s = rule.replace(s)
+	}
+	return s
+}
+
+// replace 替换 s 中命中正则规则的片段，未通过校验或紧邻数字的片段保持不变
+func (rule RedactRule) replace(s string) string {
+	var b strings.Builder
+	last, replaced := 0, false
+	for _, loc := range rule.pattern.FindAllStringIndex(s, -1) {
+		match := s[loc[0]:loc[1]]
+		if rule.validate != nil && !rule.validate(match) {
+			continue
+		}
+		if rule.digitEnd && (loc[0] > 0 && isDigit(s[loc[0]-1]) || loc[1] < len(s) && isDigit(s[loc[1]])) {
+			continue
+		}
+		b.WriteString(s[last:loc[0]])
+		b.WriteString(rule.mask(match))
+		last, replaced = loc[1], true
+	}
+	if !replaced {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// isDigit 是否为 ASCII 数字
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// redactJSON 脱敏 JSON 字符串（如请求体），不是合法 JSON 时返回 false
+func (r *redactor) redactJSON(s string) (string, bool) {
+	trimmed := strings.TrimSpace(s)
+	if len(trimmed) < 2 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return "", false
+	}
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil || dec.More() {
+		return "", false
+	}
+	doc, changed := r.redactValue("", doc)
+	if !changed {
+		return s, true
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return "", false
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), true
+}
+
+// jsonCopy 通过 JSON 编解码将任意值复制为 map / slice 结构
+func jsonCopy(v any) (any, bool) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+// redactedObject 将脱敏后的字段展开写入编码器，用于 Inline 字段
+type redactedObject map[string]any
+
+// MarshalLogObject 实现 zapcore.ObjectMarshaler
+func (o redactedObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for k, v := range o {
+		zap.Any(k, v).AddTo(enc)
+	}
+	return nil
+}
+
+// redactCore 在字段编码前按规则脱敏，作用于所有输出
+type redactCore struct {
+	zapcore.Core
+	r *redactor
+}
+
+// With 实现 zapcore.Core
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.r.redactFields(fields)), r: c.r}
+}
+
+// Check 实现 zapcore.Core
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core
+// 内层是多个输出组成的 Tee，需要经过内层 Check 才能保留各输出的级别和限流
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ce := c.Core.Check(ent, nil); ce != nil {
+		ce.Write(c.r.redactFields(fields)...)
+	}
+	return nil
+}
+
+// fieldString 返回字段值的字符串形式，供 key 规则脱敏
+func fieldString(f zapcore.Field) string {
+	if f.Type == zapcore.StringType {
+		return f.String
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	return valueString(enc.Fields[f.Key])
+}
+
+// valueString 返回值的字符串形式，map 和 slice 编码为 JSON
+func valueString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case map[string]any, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// matchRedactKey 判断统一格式后的字段名是否包含任一关键字
+func matchRedactKey(key string, keys []string) bool {
+	for _, k := range keys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeRedactKey 统一字段名的大小写和分隔符
+func normalizeRedactKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', '.', ' ':
+			return -1
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, key)
+}
+
+// luhnValid 银行卡号 Luhn 校验
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+// idNumberWeights 18 位身份证号校验位加权因子
+var idNumberWeights = [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+
+// idNumberValid 18 位居民身份证号校验位验证（GB 11643）
+func idNumberValid(s string) bool {
+	if len(s) != 18 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		sum += int(s[i]-'0') * idNumberWeights[i]
+	}
+	check := "10X98765432"[sum%11]
+	last := s[17]
+	if last == 'x' {
+		last = 'X'
+	}
+	return last == check
+}
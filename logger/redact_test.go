@@ -0,0 +1,225 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestMaskers(t *testing.T) {
+	tests := []struct {
+		name  string
+		mask  Masker
+		value string
+		want  string
+	}{
+		{"all", MaskAll(), "secret-value", "******"},
+		{"keep last", MaskKeepLast(4), "6222020012345678", "************5678"},
+		{"keep first last", MaskKeepFirstLast(3, 4), "13812345678", "138****5678"},
+		{"too short", MaskKeepLast(4), "123", "******"},
+		{"email", MaskEmail(), "alice@example.com", "a****@example.com"},
+		{"not email", MaskEmail(), "alice", "******"},
+		{"hash", MaskHash(), "alice", MaskHash()("alice")},
+	}
+	for _, tt := range tests {
+		if got := tt.mask(tt.value); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if MaskHash()("alice") == MaskHash()("bob") {
+		t.Error("hash of different values should differ")
+	}
+}
+
+func TestRedactString(t *testing.T) {
+	r := newRedactor(nil)
+	tests := []struct {
+		key, in, want string
+	}{
+		{"msg", "contact alice@example.com now", "contact a****@example.com now"},
+		{"msg", "phone 13812345678", "phone 138****5678"},
+		{"msg", "phone +86 13812345678", "phone 138****5678"},
+		{"msg", "+8613812345678", "138****5678"},
+		{"msg", "8613812345678", "138****5678"},
+		{"msg", "tel:13812345678,13912345678", "tel:138****5678,139****5678"},
+		{"msg", "order 213812345678", "order 213812345678"}, // 前后紧邻数字时不是手机号
+		{"card_no", "4111 1111 1111 1111", "***************1111"},
+		{"card_no", "1234567890123", "1234567890123"}, // Luhn 校验不通过
+		{"id_card", "11010519491231002X", "110105********002X"},
+		{"id_no", "110105194912310021", "110105194912310021"},           // 校验位错误
+		{"msg", "card 4111 1111 1111 1111", "card 4111 1111 1111 1111"}, // 字段名不匹配时不检查
+		{"body", `{"user":"bob","password":"p@ss","email":"bob@example.com"}`,
+			`{"email":"b****@example.com","password":"******","user":"bob"}`},
+		{"body", `{"cards":["4111111111111111"],"order_id":"4111111111111111"}`,
+			`{"cards":["************1111"],"order_id":"4111111111111111"}`},
+		{"body", `[{"Access-Token":"abc"}]`, `[{"Access-Token":"******"}]`},
+		{"body", `{"n":1}`, `{"n":1}`},
+		{"body", `{broken json password`, `{broken json password`},
+	}
+	for _, tt := range tests {
+		if got := r.redactString(tt.key, tt.in); got != tt.want {
+			t.Errorf("redactString(%q, %q) = %q, want %q", tt.key, tt.in, got, tt.want)
+		}
+	}
+
+	all := newRedactor([]RedactRule{RedactCardNumbers(nil).ForKeys()})
+	if got := all.redactString("msg", "card 4111 1111 1111 1111"); got != "card ***************1111" {
+		t.Errorf("unscoped card rule: got %q", got)
+	}
+}
+
+func TestRedactLuhnValidOrderIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := New(WithStdout(WithOutputLevel(FatalLevel)), WithFormat(JSONFormat), WithFile(path), WithRedaction())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	// 以下 ID 均能通过 Luhn 或身份证校验位验证，但字段名不是卡号或证件号，不应脱敏
+	ids := []string{"4111111111111111", "1234567890123452", "11010519491231002X", "7992739871300000000"}
+	for _, id := range ids {
+		if !luhnValid(strings.TrimSuffix(id, "X")) && !idNumberValid(id) {
+			t.Fatalf("test id %s should pass a checksum", id)
+		}
+	}
+	l.Info(context.Background(), "order created",
+		"order_id", ids[0],
+		"trace_id", ids[1],
+		"request_id", ids[2],
+		"snowflake", ids[3],
+		"order_num", int64(4111111111111111),
+		"card_no", int64(4111111111111111),
+	)
+	_ = l.Sync()
+
+	lines := readJSONLines(t, path)
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines", len(lines))
+	}
+	got := lines[0]
+	for key, want := range map[string]string{
+		"order_id":   ids[0],
+		"trace_id":   ids[1],
+		"request_id": ids[2],
+		"snowflake":  ids[3],
+		"card_no":    "************1111",
+	} {
+		if got[key] != want {
+			t.Errorf("%s = %v, want %q", key, got[key], want)
+		}
+	}
+	if got["order_num"] != float64(4111111111111111) {
+		t.Errorf("order_num = %v", got["order_num"])
+	}
+}
+
+func TestWithRedaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := New(WithStdout(WithOutputLevel(FatalLevel)), WithFormat(JSONFormat), WithFile(path), WithRedaction())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	profile := map[string]any{"name": "bob", "api_key": "k-123", "phone": "13812345678"}
+	l.With("authorization", "Bearer abc").Info(context.Background(), "login",
+		"password", "hunter2",
+		"db_password", 123456,
+		"email", "bob@example.com",
+		"profile", profile,
+		"req_body", `{"card":"4111111111111111","nested":{"secret":"x"}}`,
+		Group("user", String("token", "t-1"), String("id", "42")),
+		errors.New("send to bob@example.com failed"),
+	)
+	_ = l.Sync()
+
+	lines := readJSONLines(t, path)
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines", len(lines))
+	}
+	got := lines[0]
+	for key, want := range map[string]string{
+		"authorization": "******",
+		"password":      "******",
+		"db_password":   "******",
+		"email":         "b****@example.com",
+		"req_body":      `{"card":"************1111","nested":{"secret":"******"}}`,
+		"error":         "send to b****@example.com failed",
+	} {
+		if got[key] != want {
+			t.Errorf("%s = %v, want %q", key, got[key], want)
+		}
+	}
+
+	p, _ := got["profile"].(map[string]any)
+	if p["api_key"] != "******" || p["phone"] != "138****5678" || p["name"] != "bob" {
+		t.Errorf("profile = %v", p)
+	}
+	if profile["api_key"] != "k-123" {
+		t.Errorf("caller's map was modified: %v", profile)
+	}
+	u, _ := got["user"].(map[string]any)
+	if u["token"] != "******" || u["id"] != "42" {
+		t.Errorf("user = %v", u)
+	}
+}
+
+func TestWithRedactionCustomRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := New(WithStdout(WithOutputLevel(FatalLevel)), WithFormat(JSONFormat), WithFile(path), WithRedaction(
+		RedactKeys(MaskKeepLast(4), "account"),
+		RedactPattern(regexp.MustCompile(`sk-[a-z0-9]+`), nil),
+	))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	l.Info(context.Background(), "custom",
+		"bank_account", "6222020012345678",
+		"note", "key is sk-abc123",
+		"password", "kept", // 自定义规则不包含默认规则
+	)
+	_ = l.Sync()
+
+	got := readJSONLines(t, path)[0]
+	if got["bank_account"] != "************5678" {
+		t.Errorf("bank_account = %v", got["bank_account"])
+	}
+	if got["note"] != "key is ******" {
+		t.Errorf("note = %v", got["note"])
+	}
+	if got["password"] != "kept" {
+		t.Errorf("password = %v", got["password"])
+	}
+}
+
+func TestRedactFieldsUnchanged(t *testing.T) {
+	r := newRedactor(nil)
+	fields := convertToZapFields(String("user", "bob"), Int("count", 3))
+	out := r.redactFields(fields)
+	if &out[0] != &fields[0] {
+		t.Error("fields without sensitive data should not be copied")
+	}
+}
+
+func TestWithRedactionKeepsOutputLevels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := New(
+		WithStdout(WithOutputLevel(FatalLevel)),
+		WithFormat(JSONFormat),
+		WithFile(path, WithOutputLevel(WarnLevel)),
+		WithRedaction(),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	l.Info(context.Background(), "below file level", "password", "x")
+	l.Warn(context.Background(), "written", "password", "x")
+	_ = l.Sync()
+
+	lines := readJSONLines(t, path)
+	if len(lines) != 1 || lines[0]["msg"] != "written" || lines[0]["password"] != "******" {
+		t.Fatalf("unexpected lines: %v", lines)
+	}
+}
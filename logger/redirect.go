@@ -0,0 +1,228 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/grpclog"
+)
+
+// 第三方日志使用的模块名，可通过 WithModuleLevel 单独调整级别
+const (
+	stdLogModule = "stdlog"
+	grpcModule   = "grpc"
+)
+
+// loggerDir 本包源码目录，查找调用位置时跳过其中的非测试文件
+var loggerDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// internalLogf 输出 logger 自身的错误信息
+// 直接写入 stderr 而不经过标准库 log：RedirectStdLog 之后 log 会写回 logger，
+// 在输出内部（如文件切割时持有锁）调用会造成递归
+func internalLogf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "%s [Logger] %s\n", time.Now().Format("2006/01/02 15:04:05"), fmt.Sprintf(format, args...))
+}
+
+// RedirectStdLog 将标准库 log 包的输出以 level 级别写入默认 logger（模块名 stdlog），返回恢复原输出的函数
+//
+// 每次写入时获取默认 logger，之后调用 Init 或 SetDefault 同样生效；caller 为调用 log.Printf 等函数的位置。
+// log.Fatal / log.Panic 由 log 包自身退出或 panic，不会调用退出回调和刷新输出，建议改用 logger.Fatal。
+func RedirectStdLog(level Level) func() {
+	prevOutput, prevFlags, prevPrefix := log.Writer(), log.Flags(), log.Prefix()
+
+	log.SetOutput(&stdLogWriter{level: level})
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	return func() {
+		log.SetOutput(prevOutput)
+		log.SetFlags(prevFlags)
+		log.SetPrefix(prevPrefix)
+	}
+}
+
+// stdLogWriter 将标准库 log 的每次输出写为一条日志
+type stdLogWriter struct {
+	level Level
+
+	mu    sync.Mutex
+	base  Logger // 生成 named 时的默认 logger
+	named Logger
+}
+
+// Write 实现 io.Writer
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimSuffix(string(p), "\n")
+	logExternal(w.logger(), w.level, msg, "log.")
+	return len(p), nil
+}
+
+// logger 返回默认 logger 的 stdlog 模块 logger，默认 logger 变化时重新创建
+func (w *stdLogWriter) logger() Logger {
+	l := Default()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.base != l {
+		w.base, w.named = l, l.Named(stdLogModule)
+	}
+	return w.named
+}
+
+// ReplaceZapGlobals 将 zap.L() / zap.S() 替换为默认 logger，使直接使用 zap 全局 logger 的库写入 kit 输出
+// 返回恢复原 logger 的函数；替换时获取默认 logger，之后调用 Init 需要重新替换。
+// 默认 logger 不是由 New 创建时（如 FromSlog）不做替换。
+func ReplaceZapGlobals() func() {
+	zl, ok := Default().(*zapLogger)
+	if !ok {
+		return func() {}
+	}
+	// 抵消 Info 等包装方法的 caller skip，指向调用 zap 的位置
+	return zap.ReplaceGlobals(zl.logger.WithOptions(zap.AddCallerSkip(-2)))
+}
+
+// grpcLogger 将 gRPC 内部日志写入 kit logger 的 grpclog.LoggerV2 实现
+type grpcLogger struct {
+	logger    Logger
+	verbosity int
+}
+
+// NewGRPCLogger 返回写入 l 的 grpclog.LoggerV2（模块名 grpc），通过 grpclog.SetLoggerV2 设置：
+//
+//	grpclog.SetLoggerV2(logger.NewGRPCLogger(logger.Default()))
+//
+// gRPC 的 Info 日志较多，可通过 WithModuleLevel("grpc", WarnLevel) 调整；
+// 详细日志级别读取环境变量 GRPC_GO_LOG_VERBOSITY_LEVEL，与 gRPC 默认 logger 一致。
+func NewGRPCLogger(l Logger) grpclog.LoggerV2 {
+	verbosity, _ := strconv.Atoi(os.Getenv("GRPC_GO_LOG_VERBOSITY_LEVEL"))
+	return &grpcLogger{logger: l.Named(grpcModule), verbosity: verbosity}
+}
+
+func (g *grpcLogger) Info(args ...any)   { g.log(InfoLevel, fmt.Sprint(args...)) }
+func (g *grpcLogger) Infoln(args ...any) { g.log(InfoLevel, sprintln(args...)) }
+func (g *grpcLogger) Infof(format string, args ...any) {
+	g.log(InfoLevel, fmt.Sprintf(format, args...))
+}
+
+func (g *grpcLogger) Warning(args ...any)   { g.log(WarnLevel, fmt.Sprint(args...)) }
+func (g *grpcLogger) Warningln(args ...any) { g.log(WarnLevel, sprintln(args...)) }
+func (g *grpcLogger) Warningf(format string, args ...any) {
+	g.log(WarnLevel, fmt.Sprintf(format, args...))
+}
+
+func (g *grpcLogger) Error(args ...any)   { g.log(ErrorLevel, fmt.Sprint(args...)) }
+func (g *grpcLogger) Errorln(args ...any) { g.log(ErrorLevel, sprintln(args...)) }
+func (g *grpcLogger) Errorf(format string, args ...any) {
+	g.log(ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+func (g *grpcLogger) Fatal(args ...any)   { g.log(FatalLevel, fmt.Sprint(args...)) }
+func (g *grpcLogger) Fatalln(args ...any) { g.log(FatalLevel, sprintln(args...)) }
+func (g *grpcLogger) Fatalf(format string, args ...any) {
+	g.log(FatalLevel, fmt.Sprintf(format, args...))
+}
+
+// V 实现 grpclog.LoggerV2
+func (g *grpcLogger) V(l int) bool {
+	return l <= g.verbosity
+}
+
+// log 记录日志，caller 为 gRPC 中调用 grpclog 的位置
+func (g *grpcLogger) log(level Level, msg string) {
+	logExternal(g.logger, level, msg, "google.golang.org/grpc/grpclog.", "google.golang.org/grpc/internal/grpclog.")
+}
+
+// sprintln 与 fmt.Sprintln 相同但不带结尾换行
+func sprintln(args ...any) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
+
+// logExternal 记录来自第三方日志接口的日志
+// caller 为调用栈中第一个不属于本包和 skip 前缀（函数全名前缀）的位置；Fatal 级别写入后按 Fatal 处理退出
+func logExternal(l Logger, level Level, msg string, skip ...string) {
+	zl, ok := l.(*zapLogger)
+	if !ok {
+		logAt(l, level, msg)
+		return
+	}
+
+	ent := zapcore.Entry{
+		Level:      zapLevel(level),
+		Time:       time.Now(),
+		LoggerName: zl.logger.Name(),
+		Message:    msg,
+	}
+	if zl.opts.caller {
+		ent.Caller = externalCaller(skip)
+	}
+	if ce := zl.logger.Core().Check(ent, nil); ce != nil {
+		ce.Write()
+	}
+	if level == FatalLevel && zl.fatal != nil {
+		zl.fatal.exit(msg)
+	}
+}
+
+// logAt 按级别调用 Logger 的方法
+func logAt(l Logger, level Level, msg string) {
+	ctx := context.Background()
+	switch level {
+	case DebugLevel:
+		l.Debug(ctx, msg)
+	case InfoLevel:
+		l.Info(ctx, msg)
+	case WarnLevel:
+		l.Warn(ctx, msg)
+	case ErrorLevel:
+		l.Error(ctx, msg)
+	default:
+		l.Fatal(ctx, msg)
+	}
+}
+
+// externalCaller 返回调用栈中第一个不属于本包和 skip 前缀的位置
+func externalCaller(skip []string) zapcore.EntryCaller {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !skipFrame(frame, skip) {
+			return zapcore.EntryCaller{
+				Defined:  true,
+				PC:       frame.PC,
+				File:     frame.File,
+				Line:     frame.Line,
+				Function: frame.Function,
+			}
+		}
+		if !more {
+			return zapcore.EntryCaller{}
+		}
+	}
+}
+
+// skipFrame 判断调用位置是否属于本包或 skip 前缀
+func skipFrame(frame runtime.Frame, skip []string) bool {
+	if filepath.Dir(frame.File) == loggerDir && !strings.HasSuffix(frame.File, "_test.go") {
+		return true
+	}
+	for _, prefix := range skip {
+		if strings.HasPrefix(frame.Function, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"log"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observeDefault 将默认 logger 替换为写入 observer 的 logger，测试结束时恢复
+func observeDefault(t *testing.T, opts ...Option) *observer.ObservedLogs {
+	t.Helper()
+	core, logs := observer.New(zapcore.DebugLevel)
+	l, err := New(append([]Option{WithLevel(DebugLevel), WithCore(core)}, opts...)...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	old := Default()
+	SetDefault(l)
+	t.Cleanup(func() { SetDefault(old) })
+	return logs
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	logs := observeDefault(t, WithModuleLevel("stdlog", InfoLevel))
+	restore := RedirectStdLog(InfoLevel)
+
+	log.Printf("[Config] reload failed: %v", "timeout")
+	log.Println("multi\nline")
+	restore()
+	if _, ok := log.Writer().(*stdLogWriter); ok {
+		t.Error("restore should reset the log output")
+	}
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries: %+v", len(entries), entries)
+	}
+	e := entries[0]
+	if e.Message != "[Config] reload failed: timeout" || e.LoggerName != "stdlog" || e.Level != zapcore.InfoLevel {
+		t.Errorf("entry = %+v", e.Entry)
+	}
+	if !strings.HasSuffix(e.Caller.File, "redirect_test.go") {
+		t.Errorf("caller = %s", e.Caller.TrimmedPath())
+	}
+	if entries[1].Message != "multi\nline" {
+		t.Errorf("message = %q", entries[1].Message)
+	}
+}
+
+func TestRedirectStdLogModuleLevel(t *testing.T) {
+	logs := observeDefault(t, WithModuleLevel("stdlog", ErrorLevel))
+	defer RedirectStdLog(WarnLevel)()
+
+	log.Print("suppressed by module level")
+	if logs.Len() != 0 {
+		t.Fatalf("expected stdlog entries below module level to be dropped, got %+v", logs.All())
+	}
+}
+
+func TestReplaceZapGlobals(t *testing.T) {
+	logs := observeDefault(t)
+	restore := ReplaceZapGlobals()
+	zap.L().Warn("from zap", zap.Int("attempt", 2))
+	zap.S().Infow("from sugar", "k", "v")
+	restore()
+	zap.L().Warn("after restore")
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries", len(entries))
+	}
+	if entries[0].Message != "from zap" || entries[0].ContextMap()["attempt"] != int64(2) {
+		t.Errorf("entry = %+v", entries[0])
+	}
+	if !strings.HasSuffix(entries[0].Caller.File, "redirect_test.go") {
+		t.Errorf("caller = %s", entries[0].Caller.TrimmedPath())
+	}
+}
+
+func TestGRPCLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l, err := New(WithLevel(DebugLevel), WithCore(core), WithFatalPanic())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	g := NewGRPCLogger(l)
+
+	g.Infof("channel %d state: %s", 1, "READY")
+	g.Warningln("retrying", 3)
+	g.Error("transport closed")
+	if g.V(2) {
+		t.Error("verbosity 2 should be disabled by default")
+	}
+
+	func() {
+		defer func() {
+			if _, ok := recover().(FatalPanic); !ok {
+				t.Error("grpc Fatal should go through fatal handling")
+			}
+		}()
+		g.Fatalf("unrecoverable: %s", "bad config")
+	}()
+
+	entries := logs.All()
+	want := []struct {
+		level zapcore.Level
+		msg   string
+	}{
+		{zapcore.InfoLevel, "channel 1 state: READY"},
+		{zapcore.WarnLevel, "retrying 3"},
+		{zapcore.ErrorLevel, "transport closed"},
+		{zapcore.FatalLevel, "unrecoverable: bad config"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries", len(entries))
+	}
+	for i, w := range want {
+		if entries[i].Level != w.level || entries[i].Message != w.msg || entries[i].LoggerName != "grpc" {
+			t.Errorf("entry %d = %+v, want %v %q", i, entries[i].Entry, w.level, w.msg)
+		}
+	}
+}
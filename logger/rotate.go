@@ -0,0 +1,565 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateEvent 文件切割事件
+type RotateEvent struct {
+	Previous string    // 切割出的旧文件（启用压缩时为 .gz 文件）
+	Current  string    // 切割后正在写入的文件
+	Reason   string    // 切割原因：time 或 size
+	Time     time.Time // 切割时间
+	Removed  []string  // 按保留策略删除的旧文件
+}
+
+// rotateQueueSize 等待压缩清理和等待回调的切割事件数量上限
+const rotateQueueSize = 16
+
+// rotateVerbs 文件名模式支持的占位符及对应的时间格式
+var rotateVerbs = map[byte]string{
+	'Y': "2006",
+	'm': "01",
+	'd': "02",
+	'H': "15",
+	'M': "04",
+	'S': "05",
+}
+
+// rotateVerbPatterns 占位符对应的正则，用于匹配已切割的文件
+var rotateVerbPatterns = map[byte]string{
+	'Y': `\d{4}`,
+	'm': `\d{2}`,
+	'd': `\d{2}`,
+	'H': `\d{2}`,
+	'M': `\d{2}`,
+	'S': `\d{2}`,
+}
+
+// useFileRotator 是否需要使用内置的切割器，未使用新特性时保持 lumberjack 的行为
+func useFileRotator(cfg OutputConfig) bool {
+	return cfg.RotateInterval > 0 || cfg.MaxTotalSize > 0 || cfg.OnRotate != nil ||
+		strings.Contains(cfg.FilePath, "%")
+}
+
+// fileRotator 按时间和大小切割日志文件
+//
+// 文件路径包含占位符（如 app-%Y-%m-%d.log）时，当前文件按时间周期直接命名，
+// 同一周期内按大小切割的文件追加序号（app-2026-10-17.1.log）；
+// 否则当前文件固定为配置的路径，切割时重命名为 app-<周期>.log。
+// 压缩和清理在后台协程中执行，回调在另一个协程中执行，均不阻塞写入。
+type fileRotator struct {
+	path     string        // 配置的路径或文件名模式
+	pattern  bool          // path 是否为文件名模式
+	interval time.Duration // 按时间切割的周期，0 表示不按时间切割
+	months   int           // 按月切割的月数（%m 为 1，%Y 为 12），与 interval 互斥
+	maxSize  int64         // 按大小切割的阈值（字节），0 表示不按大小切割
+	maxAge   time.Duration
+	backups  int
+	maxTotal int64
+	compress bool
+	onRotate func(RotateEvent)
+	now      func() time.Time
+
+	mu        sync.Mutex
+	file      *os.File
+	current   string
+	size      int64
+	periodBeg time.Time // 当前周期的开始时间
+	periodEnd time.Time // 当前周期的结束时间，零值表示不按时间切割
+	index     int
+
+	millCh   chan RotateEvent
+	notifyCh chan RotateEvent // 交给回调协程的事件，回调较慢时不影响压缩和清理
+	millDone chan struct{}
+	closed   bool
+	matcher  *regexp.Regexp
+}
+
+// newFileRotator 创建切割器，文件在首次写入时打开
+func newFileRotator(cfg OutputConfig) (*fileRotator, error) {
+	dir := filepath.Dir(cfg.FilePath)
+	if strings.Contains(dir, "%") {
+		return nil, fmt.Errorf("file pattern placeholders are only allowed in the file name: %s", cfg.FilePath)
+	}
+
+	r := &fileRotator{
+		path:     cfg.FilePath,
+		pattern:  strings.Contains(filepath.Base(cfg.FilePath), "%"),
+		interval: cfg.RotateInterval,
+		maxSize:  int64(cfg.MaxSize) << 20,
+		maxAge:   time.Duration(cfg.MaxAge) * 24 * time.Hour,
+		backups:  cfg.MaxBackups,
+		maxTotal: int64(cfg.MaxTotalSize) << 20,
+		compress: cfg.Compress,
+		onRotate: cfg.OnRotate,
+		now:      time.Now,
+		millCh:   make(chan RotateEvent, rotateQueueSize),
+		notifyCh: make(chan RotateEvent, rotateQueueSize),
+		millDone: make(chan struct{}),
+	}
+
+	if r.pattern {
+		if err := validateFilePattern(r.path); err != nil {
+			return nil, err
+		}
+		if r.interval == 0 {
+			r.interval, r.months = patternPeriod(r.path)
+		}
+	}
+	// 默认的备份数量针对按大小切割，按天切割时只保留 3 天与 MaxAge 的 7 天相矛盾，未显式设置时不限制
+	if cfg.defaultBackups && r.periodic() {
+		r.backups = 0
+	}
+	r.matcher = r.backupMatcher()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log dir: %w", err)
+	}
+
+	go r.mill(r.millCh)
+	go r.notify()
+	return r, nil
+}
+
+// Write 实现 io.Writer，写入前按需切割
+func (r *fileRotator) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, os.ErrClosed
+	}
+
+	now := r.now()
+	switch {
+	case r.file == nil:
+		if err := r.open(now); err != nil {
+			return 0, err
+		}
+	case !r.periodEnd.IsZero() && !now.Before(r.periodEnd):
+		if err := r.rotate(now, "time"); err != nil {
+			return 0, err
+		}
+	case r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize:
+		if err := r.rotate(now, "size"); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Sync 实现 zapcore.WriteSyncer
+func (r *fileRotator) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Sync()
+}
+
+// Close 关闭当前文件，并等待后台的压缩、清理和回调完成，关闭后的写入返回 os.ErrClosed
+func (r *fileRotator) Close() error {
+	r.mu.Lock()
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+	if !r.closed {
+		close(r.millCh)
+		r.closed = true
+	}
+	r.mu.Unlock()
+
+	<-r.millDone
+	return err
+}
+
+// open 打开当前周期的文件，已存在时追加写入
+func (r *fileRotator) open(now time.Time) error {
+	start := r.periodStart(now)
+	r.periodBeg, r.periodEnd = start, time.Time{}
+	switch {
+	case r.months > 0:
+		r.periodEnd = start.AddDate(0, r.months, 0)
+	case r.interval > 0:
+		r.periodEnd = start.Add(r.interval)
+	}
+
+	if r.pattern {
+		// 同一周期内可能已有按大小切割的文件，继续写入最新的一个
+		base := expandFilePattern(r.path, start)
+		r.index = 0
+		for {
+			info, err := os.Stat(indexedName(base, r.index))
+			if err != nil || r.maxSize == 0 || info.Size() < r.maxSize {
+				break
+			}
+			r.index++
+		}
+		return r.openFile(indexedName(base, r.index))
+	}
+
+	// 进程重启后，上一周期遗留的文件先切割出去
+	if info, err := os.Stat(r.path); err == nil && r.periodic() && info.ModTime().Before(start) {
+		backup := r.backupName(r.periodStart(info.ModTime()))
+		if err := os.Rename(r.path, backup); err != nil {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+		r.enqueue(RotateEvent{Previous: backup, Current: r.path, Reason: "time", Time: now})
+	}
+	return r.openFile(r.path)
+}
+
+// openFile 以追加方式打开文件
+func (r *fileRotator) openFile(name string) error {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file, r.current, r.size = f, name, info.Size()
+	return nil
+}
+
+// rotate 切割当前文件并打开新文件
+func (r *fileRotator) rotate(now time.Time, reason string) error {
+	prevStart := r.periodBeg
+	if err := r.file.Close(); err != nil {
+		internalLogf("failed to close log file %s: %v", r.current, err)
+	}
+	r.file = nil
+	previous := r.current
+
+	if r.pattern {
+		if reason == "size" {
+			r.index++
+			if err := r.openFile(indexedName(expandFilePattern(r.path, r.periodStart(now)), r.index)); err != nil {
+				return err
+			}
+		} else if err := r.open(now); err != nil {
+			return err
+		}
+	} else {
+		stamp := prevStart
+		if r.interval == 0 {
+			stamp = now
+		}
+		backup := r.backupName(stamp)
+		if err := os.Rename(r.path, backup); err != nil {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+		previous = backup
+		if err := r.open(now); err != nil {
+			return err
+		}
+	}
+
+	// 切割周期短于文件名模式的周期时（如 app-%Y%m%d.log 按小时切割），新周期仍写入同一个文件，
+	// 该文件正在使用，不能压缩或作为旧文件清理
+	if r.current == previous {
+		return nil
+	}
+	r.enqueue(RotateEvent{Previous: previous, Current: r.current, Reason: reason, Time: now})
+	return nil
+}
+
+// enqueue 将切割事件交给后台协程处理
+// 在 Write 中持有锁时调用，队列满时丢弃事件（该文件不压缩，清理在下一次切割时进行），不阻塞写入
+func (r *fileRotator) enqueue(ev RotateEvent) {
+	if r.closed {
+		return
+	}
+	select {
+	case r.millCh <- ev:
+	default:
+		internalLogf("rotation queue full, skipped compress and callback for %s", ev.Previous)
+	}
+}
+
+// mill 后台协程：压缩切割出的文件、按保留策略清理，再交给回调协程
+func (r *fileRotator) mill(events <-chan RotateEvent) {
+	defer close(r.notifyCh)
+	for ev := range events {
+		if r.compress {
+			if gz, err := compressFile(ev.Previous); err != nil {
+				internalLogf("failed to compress log file %s: %v", ev.Previous, err)
+			} else {
+				ev.Previous = gz
+			}
+		}
+		ev.Removed = r.cleanup(ev.Current)
+		if r.onRotate == nil {
+			continue
+		}
+		select {
+		case r.notifyCh <- ev:
+		default:
+			internalLogf("rotation callback is too slow, skipped event for %s", ev.Previous)
+		}
+	}
+}
+
+// notify 回调协程：依次调用 OnRotate，回调中的 panic 被恢复
+func (r *fileRotator) notify() {
+	defer close(r.millDone)
+	for ev := range r.notifyCh {
+		func() {
+			defer func() {
+				if v := recover(); v != nil {
+					internalLogf("rotation callback panicked: %v", v)
+				}
+			}()
+			r.onRotate(ev)
+		}()
+	}
+}
+
+// cleanup 按保留天数、数量和总大小删除旧文件，返回被删除的文件
+func (r *fileRotator) cleanup(current string) []string {
+	if r.maxAge <= 0 && r.backups <= 0 && r.maxTotal <= 0 {
+		return nil
+	}
+
+	dir := filepath.Dir(r.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	type backup struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var backups []backup
+	var total int64
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if path == current {
+			total += info.Size()
+			continue
+		}
+		if !r.matcher.MatchString(e.Name()) {
+			continue
+		}
+		backups = append(backups, backup{path: path, size: info.Size(), modTime: info.ModTime()})
+	}
+
+	// 从新到旧
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].modTime.After(backups[j].modTime)
+	})
+
+	var removed []string
+	cutoff := r.now().Add(-r.maxAge)
+	for i, b := range backups {
+		remove := (r.backups > 0 && i >= r.backups) ||
+			(r.maxAge > 0 && b.modTime.Before(cutoff)) ||
+			(r.maxTotal > 0 && total+b.size > r.maxTotal)
+		if !remove {
+			total += b.size
+			continue
+		}
+		if err := os.Remove(b.path); err != nil {
+			internalLogf("failed to remove log file %s: %v", b.path, err)
+			total += b.size
+			continue
+		}
+		removed = append(removed, b.path)
+	}
+	return removed
+}
+
+// periodic 是否按时间切割
+func (r *fileRotator) periodic() bool {
+	return r.interval > 0 || r.months > 0
+}
+
+// periodStart 返回 t 所在切割周期的开始时间
+// 按月、按年的周期从当月（当年）1 日零点开始；一天以内的周期按本地时间的零点对齐（如每小时、每天），
+// 更长的周期按 interval 取整
+func (r *fileRotator) periodStart(t time.Time) time.Time {
+	if r.months > 0 {
+		month := (int(t.Month())-1)/r.months*r.months + 1
+		return time.Date(t.Year(), time.Month(month), 1, 0, 0, 0, 0, t.Location())
+	}
+	if r.interval <= 0 {
+		return t
+	}
+	if r.interval > 24*time.Hour {
+		return t.Truncate(r.interval)
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return midnight.Add(t.Sub(midnight) / r.interval * r.interval)
+}
+
+// backupName 返回固定路径模式下切割出的文件名，如 app-2026-10-17.log，重名时追加序号
+func (r *fileRotator) backupName(t time.Time) string {
+	ext := filepath.Ext(r.path)
+	prefix := strings.TrimSuffix(r.path, ext)
+
+	layout := "2006-01-02T15-04-05.000"
+	switch {
+	case r.interval >= 24*time.Hour:
+		layout = "2006-01-02"
+	case r.interval >= time.Hour:
+		layout = "2006-01-02T15"
+	case r.interval >= time.Minute:
+		layout = "2006-01-02T15-04"
+	}
+
+	base := prefix + "-" + t.Format(layout) + ext
+	for i := 0; ; i++ {
+		name := indexedName(base, i)
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			if _, err := os.Stat(name + ".gz"); os.IsNotExist(err) {
+				return name
+			}
+		}
+	}
+}
+
+// backupMatcher 返回匹配切割出的文件名（含序号和 .gz 后缀）的正则
+func (r *fileRotator) backupMatcher() *regexp.Regexp {
+	name := filepath.Base(r.path)
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+
+	var expr string
+	if r.pattern {
+		var b strings.Builder
+		for i := 0; i < len(stem); i++ {
+			if stem[i] == '%' && i+1 < len(stem) {
+				if p, ok := rotateVerbPatterns[stem[i+1]]; ok {
+					b.WriteString(p)
+					i++
+					continue
+				}
+			}
+			b.WriteString(regexp.QuoteMeta(stem[i : i+1]))
+		}
+		expr = b.String()
+	} else {
+		expr = regexp.QuoteMeta(stem) + `-\d{4}-\d{2}-\d{2}[T\d.-]*`
+	}
+	return regexp.MustCompile(`^` + expr + `(\.\d+)?` + regexp.QuoteMeta(ext) + `(\.gz)?$`)
+}
+
+// validateFilePattern 校验文件名模式中的占位符
+func validateFilePattern(pattern string) error {
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' {
+			continue
+		}
+		if i+1 >= len(pattern) {
+			return fmt.Errorf("invalid file pattern %q: trailing %%", pattern)
+		}
+		if _, ok := rotateVerbs[pattern[i+1]]; !ok {
+			return fmt.Errorf("invalid file pattern %q: unknown placeholder %%%c", pattern, pattern[i+1])
+		}
+		i++
+	}
+	return nil
+}
+
+// expandFilePattern 将文件名模式中的占位符替换为时间
+func expandFilePattern(pattern string, t time.Time) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '%' && i+1 < len(pattern) {
+			if layout, ok := rotateVerbs[pattern[i+1]]; ok {
+				b.WriteString(t.Format(layout))
+				i++
+				continue
+			}
+		}
+		b.WriteByte(pattern[i])
+	}
+	return b.String()
+}
+
+// patternPeriod 根据文件名模式中最细的占位符推断切割周期，%m 和 %Y 返回按月切割的月数
+func patternPeriod(pattern string) (time.Duration, int) {
+	switch {
+	case strings.Contains(pattern, "%S"):
+		return time.Second, 0
+	case strings.Contains(pattern, "%M"):
+		return time.Minute, 0
+	case strings.Contains(pattern, "%H"):
+		return time.Hour, 0
+	case strings.Contains(pattern, "%d"):
+		return 24 * time.Hour, 0
+	case strings.Contains(pattern, "%m"):
+		return 0, 1
+	case strings.Contains(pattern, "%Y"):
+		return 0, 12
+	default:
+		return 0, 0
+	}
+}
+
+// indexedName 在扩展名前插入序号，序号为 0 时返回原文件名
+func indexedName(name string, index int) string {
+	if index == 0 {
+		return name
+	}
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + strconv.Itoa(index) + ext
+}
+
+// compressFile 将文件压缩为 .gz 并删除原文件
+func compressFile(path string) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dst := path + ".gz"
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return "", err
+	}
+	gz := gzip.NewWriter(f)
+	_, err = io.Copy(gz, src)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return "", err
+	}
+
+	src.Close()
+	return dst, os.Remove(path)
+}
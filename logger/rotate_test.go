@@ -0,0 +1,388 @@
+package logger
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock 可手动推进的时钟
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	c.now = t
+	c.mu.Unlock()
+}
+
+// newTestRotator 创建使用 fakeClock 的切割器
+func newTestRotator(t *testing.T, cfg OutputConfig, start time.Time) (*fileRotator, *fakeClock) {
+	t.Helper()
+	r, err := newFileRotator(cfg)
+	if err != nil {
+		t.Fatalf("newFileRotator: %v", err)
+	}
+	clock := &fakeClock{now: start}
+	r.now = clock.Now
+	return r, clock
+}
+
+// listDir 返回目录中的文件名
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestFileRotatorDailyPattern(t *testing.T) {
+	dir := t.TempDir()
+	var events []RotateEvent
+	r, clock := newTestRotator(t, OutputConfig{
+		FilePath: filepath.Join(dir, "app-%Y-%m-%d.log"),
+		Compress: true,
+		OnRotate: func(ev RotateEvent) { events = append(events, ev) },
+	}, time.Date(2026, 10, 17, 23, 59, 0, 0, time.Local))
+
+	r.Write([]byte("day1\n"))
+	clock.Set(time.Date(2026, 10, 18, 0, 0, 1, 0, time.Local))
+	r.Write([]byte("day2\n"))
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	want := []string{"app-2026-10-17.log.gz", "app-2026-10-18.log"}
+	if got := listDir(t, dir); !equalStrings(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("OnRotate called %d times, want 1", len(events))
+	}
+	ev := events[0]
+	if ev.Reason != "time" || filepath.Base(ev.Previous) != "app-2026-10-17.log.gz" || filepath.Base(ev.Current) != "app-2026-10-18.log" {
+		t.Errorf("event = %+v", ev)
+	}
+
+	f, err := os.Open(ev.Previous)
+	if err != nil {
+		t.Fatalf("open gz: %v", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	if data, _ := io.ReadAll(zr); string(data) != "day1\n" {
+		t.Errorf("compressed content = %q, want day1", data)
+	}
+}
+
+func TestFileRotatorSizeWithinPeriod(t *testing.T) {
+	dir := t.TempDir()
+	r, _ := newTestRotator(t, OutputConfig{FilePath: filepath.Join(dir, "app-%Y%m%d.log")},
+		time.Date(2026, 10, 17, 10, 0, 0, 0, time.Local))
+	r.maxSize = 10
+
+	for i := 0; i < 3; i++ {
+		r.Write([]byte("12345678\n"))
+	}
+	r.Close()
+
+	want := []string{"app-20261017.1.log", "app-20261017.2.log", "app-20261017.log"}
+	if got := listDir(t, dir); !equalStrings(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
+
+func TestFileRotatorFixedPathHourly(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	r, clock := newTestRotator(t, OutputConfig{FilePath: path, RotateInterval: time.Hour},
+		time.Date(2026, 10, 17, 10, 30, 0, 0, time.Local))
+
+	r.Write([]byte("10h\n"))
+	clock.Set(time.Date(2026, 10, 17, 11, 5, 0, 0, time.Local))
+	r.Write([]byte("11h\n"))
+	r.Close()
+
+	want := []string{"app-2026-10-17T10.log", "app.log"}
+	if got := listDir(t, dir); !equalStrings(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "11h\n" {
+		t.Errorf("current file = %q, want 11h", data)
+	}
+}
+
+// writeAndWait 写入一行；发生切割时等待后台清理完成，再将当前文件的修改时间设为 ts，保证文件新旧有序
+func writeAndWait(t *testing.T, r *fileRotator, rotated <-chan RotateEvent, ts time.Time) {
+	t.Helper()
+	prev := r.current
+	r.Write([]byte("12345678\n"))
+	if prev != "" && r.current != prev {
+		select {
+		case <-rotated:
+		case <-time.After(time.Second):
+			t.Fatal("OnRotate not called")
+		}
+	}
+	os.Chtimes(r.current, ts, ts)
+}
+
+func TestFileRotatorRetention(t *testing.T) {
+	dir := t.TempDir()
+	rotated := make(chan RotateEvent, 1)
+	var removed []string
+	r, clock := newTestRotator(t, OutputConfig{
+		FilePath:   filepath.Join(dir, "app-%Y-%m-%d.log"),
+		MaxBackups: 2,
+		OnRotate: func(ev RotateEvent) {
+			removed = append(removed, ev.Removed...)
+			rotated <- ev
+		},
+	}, time.Date(2026, 10, 10, 12, 0, 0, 0, time.Local))
+
+	for day := 10; day <= 14; day++ {
+		ts := time.Date(2026, 10, day, 12, 0, 0, 0, time.Local)
+		clock.Set(ts)
+		writeAndWait(t, r, rotated, ts)
+	}
+	r.Close()
+
+	want := []string{"app-2026-10-12.log", "app-2026-10-13.log", "app-2026-10-14.log"}
+	if got := listDir(t, dir); !equalStrings(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+	if len(removed) != 2 {
+		t.Errorf("removed = %v, want 2 files", removed)
+	}
+}
+
+func TestFileRotatorMaxTotalSize(t *testing.T) {
+	dir := t.TempDir()
+	rotated := make(chan RotateEvent, 1)
+	r, _ := newTestRotator(t, OutputConfig{
+		FilePath: filepath.Join(dir, "app-%Y-%m-%d.log"),
+		OnRotate: func(ev RotateEvent) { rotated <- ev },
+	}, time.Date(2026, 10, 17, 10, 0, 0, 0, time.Local))
+	r.maxSize = 10
+	r.maxTotal = 25
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		writeAndWait(t, r, rotated, start.Add(time.Duration(i)*time.Second))
+	}
+	r.Close()
+
+	// 每个文件 9 字节，总大小上限 25 字节：当前文件 + 最新的一个切割文件
+	want := []string{"app-2026-10-17.3.log", "app-2026-10-17.4.log"}
+	if got := listDir(t, dir); !equalStrings(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+}
+
+func TestWithFilePattern(t *testing.T) {
+	dir := t.TempDir()
+	l, err := New(
+		WithStdout(WithOutputLevel(FatalLevel)),
+		WithFile(filepath.Join(dir, "app-%Y-%m-%d.log")),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	l.Info(context.Background(), "hello")
+	_ = l.Sync()
+
+	name := "app-" + time.Now().Format("2006-01-02") + ".log"
+	if got := listDir(t, dir); len(got) != 1 || got[0] != name {
+		t.Errorf("files = %v, want [%s]", got, name)
+	}
+
+	if _, err := New(WithFile(filepath.Join(dir, "app-%Q.log"))); err == nil {
+		t.Error("New with unknown placeholder should fail")
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFileRotatorSlowCallbackDoesNotBlockWrites(t *testing.T) {
+	dir := t.TempDir()
+	block := make(chan struct{})
+	r, _ := newTestRotator(t, OutputConfig{
+		FilePath: filepath.Join(dir, "app-%Y-%m-%d.log"),
+		OnRotate: func(RotateEvent) { <-block },
+	}, time.Date(2026, 10, 17, 10, 0, 0, 0, time.Local))
+	r.maxSize = 10
+
+	// 回调一直阻塞，切割次数远超队列容量时写入仍能完成
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 4*rotateQueueSize; i++ {
+			r.Write([]byte("12345678\n"))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("writes blocked by slow OnRotate callback")
+	}
+
+	close(block)
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestWithFileDefaultBackupsForTimeRotation(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		opt  Option
+		want int
+	}{
+		{"daily pattern", WithFile(filepath.Join(dir, "app-%Y-%m-%d.log")), 0},
+		{"fixed path hourly", WithFile(filepath.Join(dir, "a.log"), WithFileRotateInterval(time.Hour)), 0},
+		{"explicit backups", WithFile(filepath.Join(dir, "b-%Y-%m-%d.log"), WithFileMaxBackups(5)), 5},
+		{"size only", WithFile(filepath.Join(dir, "c.log"), WithFileMaxTotalSize(100)), 3},
+	}
+	for _, tt := range tests {
+		o := newOptions(tt.opt)
+		cfg := o.outputs[len(o.outputs)-1].Config
+		r, err := newFileRotator(cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if r.backups != tt.want {
+			t.Errorf("%s: backups = %d, want %d", tt.name, r.backups, tt.want)
+		}
+		r.Close()
+	}
+}
+
+func TestFileRotatorMonthlyPattern(t *testing.T) {
+	dir := t.TempDir()
+	rotated := make(chan RotateEvent, 1)
+	r, clock := newTestRotator(t, OutputConfig{
+		FilePath: filepath.Join(dir, "app-%Y-%m.log"),
+		OnRotate: func(ev RotateEvent) { rotated <- ev },
+	}, time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local))
+
+	for _, ts := range []time.Time{
+		time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local),
+		time.Date(2026, 10, 31, 23, 59, 59, 0, time.Local),
+		time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local),
+		time.Date(2027, 1, 5, 8, 0, 0, 0, time.Local),
+	} {
+		clock.Set(ts)
+		writeAndWait(t, r, rotated, ts)
+	}
+	r.Close()
+
+	want := []string{"app-2026-10.log", "app-2026-11.log", "app-2027-01.log"}
+	if got := listDir(t, dir); !equalStrings(got, want) {
+		t.Errorf("files = %v, want %v", got, want)
+	}
+
+	y, _ := newTestRotator(t, OutputConfig{FilePath: filepath.Join(dir, "y-%Y.log")}, time.Now())
+	defer y.Close()
+	if y.months != 12 || !y.periodic() {
+		t.Errorf("yearly pattern: interval = %v, months = %d", y.interval, y.months)
+	}
+}
+
+func TestLoggerCloseStopsFileRotator(t *testing.T) {
+	dir := t.TempDir()
+	l, err := New(WithFile(filepath.Join(dir, "app-%Y-%m-%d.log")))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	l.Info(context.Background(), "hello")
+
+	rotators := l.(*zapLogger).rotators
+	if len(rotators) != 1 {
+		t.Fatalf("rotators = %d, want 1", len(rotators))
+	}
+	// 测试环境中 stdout 可能不支持 Sync，忽略返回的错误
+	_ = Close(l)
+
+	// millDone 在压缩协程和回调协程都退出后关闭
+	r := rotators[0]
+	select {
+	case <-r.millDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("rotator goroutines still running after Close")
+	}
+	if r.file != nil {
+		t.Error("log file still open after Close")
+	}
+	if _, err := r.Write([]byte("late\n")); err == nil {
+		t.Error("Write after Close should fail")
+	}
+}
+
+func TestFileRotatorIntervalFinerThanPattern(t *testing.T) {
+	dir := t.TempDir()
+	rotated := make(chan RotateEvent, 4)
+	r, clock := newTestRotator(t, OutputConfig{
+		FilePath:       filepath.Join(dir, "app-%Y%m%d.log"),
+		RotateInterval: time.Hour,
+		Compress:       true,
+		OnRotate:       func(ev RotateEvent) { rotated <- ev },
+	}, time.Date(2026, 10, 17, 10, 0, 0, 0, time.Local))
+
+	// 按小时切割时仍在同一天，继续写入正在使用的文件，不能被压缩
+	r.Write([]byte("first\n"))
+	clock.Set(time.Date(2026, 10, 17, 11, 0, 0, 0, time.Local))
+	r.Write([]byte("second\n"))
+	r.Write([]byte("third\n"))
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	select {
+	case ev := <-rotated:
+		t.Errorf("unexpected rotation within the same file: %+v", ev)
+	default:
+	}
+	if got := listDir(t, dir); !equalStrings(got, []string{"app-20261017.log"}) {
+		t.Fatalf("files = %v, want [app-20261017.log]", got)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "app-20261017.log"))
+	if err != nil || string(data) != "first\nsecond\nthird\n" {
+		t.Errorf("content = %q (err %v)", data, err)
+	}
+}
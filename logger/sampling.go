@@ -0,0 +1,176 @@
+package logger
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// dropSummaryMessage 丢弃统计日志的消息
+const dropSummaryMessage = "log records dropped"
+
+// dropCounter 统计被采样或限流丢弃的日志，并定期输出汇总
+// 只在发生丢弃后才启动定时器，没有丢弃时不产生任何开销和输出
+type dropCounter struct {
+	mu       sync.Mutex
+	counts   map[string]uint64
+	timer    *time.Timer
+	since    time.Time
+	interval time.Duration
+
+	// core 汇总日志写入的 core，不经过采样和限流
+	core zapcore.Core
+}
+
+// newDropCounter 创建丢弃计数器
+func newDropCounter(interval time.Duration) *dropCounter {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &dropCounter{counts: make(map[string]uint64), interval: interval}
+}
+
+// add 记录一次丢弃，source 标识丢弃原因（如 sampling、rate_limit.stdout）
+func (d *dropCounter) add(source string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.counts) == 0 {
+		d.since = time.Now()
+	}
+	d.counts[source]++
+	if d.timer == nil {
+		d.timer = time.AfterFunc(d.interval, d.flush)
+	}
+}
+
+// flush 输出并清空当前的丢弃统计
+func (d *dropCounter) flush() {
+	d.mu.Lock()
+	counts := d.counts
+	since := d.since
+	d.counts = make(map[string]uint64)
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	core := d.core
+	d.mu.Unlock()
+
+	if len(counts) == 0 || core == nil {
+		return
+	}
+
+	sources := make([]string, 0, len(counts))
+	var total uint64
+	for source, n := range counts {
+		sources = append(sources, source)
+		total += n
+	}
+	sort.Strings(sources)
+
+	fields := make([]zap.Field, 0, len(sources)+2)
+	fields = append(fields, zap.Uint64("dropped", total), zap.Duration("window", time.Since(since)))
+	for _, source := range sources {
+		fields = append(fields, zap.Uint64("dropped."+source, counts[source]))
+	}
+
+	ent := zapcore.Entry{Level: zapcore.WarnLevel, Time: time.Now(), Message: dropSummaryMessage}
+	if ce := core.Check(ent, nil); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// samplerHook 返回 zap 采样器的回调，统计被采样丢弃的日志
+func (d *dropCounter) samplerHook() zapcore.SamplerOption {
+	return zapcore.SamplerHook(func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+		if dec&zapcore.LogDropped != 0 {
+			d.add("sampling")
+		}
+	})
+}
+
+// tokenBucket 令牌桶限流器
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // 每秒补充的令牌数
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket 创建令牌桶，初始为满
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// allow 尝试获取一个令牌
+func (b *tokenBucket) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// rateLimitCore 对单个输出限流，超出速率的日志被丢弃并计数
+// Fatal 等终止程序的日志不受限流影响
+type rateLimitCore struct {
+	zapcore.Core
+	name    string
+	limiter *tokenBucket
+	drops   *dropCounter
+}
+
+// With 实现 zapcore.Core，子 core 共享同一个令牌桶
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitCore{Core: c.Core.With(fields), name: c.name, limiter: c.limiter, drops: c.drops}
+}
+
+// Check 实现 zapcore.Core
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Core.Enabled(ent.Level) {
+		return ce
+	}
+	if ent.Level < zapcore.DPanicLevel && !c.limiter.allow() {
+		c.drops.add("rate_limit." + c.name)
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// unwrapRateLimit 去掉限流包装，用于写入不应被限流的汇总日志
+func unwrapRateLimit(core zapcore.Core) zapcore.Core {
+	if c, ok := core.(*rateLimitCore); ok {
+		return c.Core
+	}
+	return core
+}
+
+// outputName 返回输出在统计中的名称
+func outputName(output Output) string {
+	switch output.Type {
+	case FileOutput:
+		return "file:" + output.Config.FilePath
+	case OTLPOutput:
+		return "otlp:" + output.Config.Endpoint
+	default:
+		return string(output.Type)
+	}
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readJSONLines 读取 JSON 格式的日志文件
+func readJSONLines(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid json line %q: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestSamplingAndDropSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	l, err := New(
+		WithFormat(JSONFormat),
+		WithStdout(WithOutputLevel(FatalLevel)),
+		WithFile(path),
+		WithSampling(2, 0, time.Minute),
+		WithDropSummaryInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		l.Error(ctx, "db unavailable", "attempt", i)
+	}
+	l.Info(ctx, "different message")
+	_ = l.Sync()
+
+	records := readJSONLines(t, path)
+	if len(records) != 4 {
+		t.Fatalf("expected 2 sampled + 1 other + 1 summary, got %d: %v", len(records), records)
+	}
+	summary := records[3]
+	if summary["msg"] != dropSummaryMessage || summary["dropped"] != float64(8) || summary["dropped.sampling"] != float64(8) {
+		t.Fatalf("unexpected summary: %v", summary)
+	}
+}
+
+func TestOutputRateLimit(t *testing.T) {
+	dir := t.TempDir()
+	limited := filepath.Join(dir, "limited.log")
+	full := filepath.Join(dir, "full.log")
+	l, err := New(
+		WithFormat(JSONFormat),
+		WithStdout(WithOutputLevel(FatalLevel)),
+		WithFile(limited, WithOutputRateLimit(0.001, 3)),
+		WithFile(full),
+		WithDropSummaryInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		l.Info(ctx, "event "+strconv.Itoa(i))
+	}
+	_ = l.Sync()
+
+	records := readJSONLines(t, limited)
+	if len(records) != 4 {
+		t.Fatalf("expected 3 records + summary in limited output, got %d", len(records))
+	}
+	if key := "dropped.rate_limit.file:" + limited; records[3][key] != float64(7) {
+		t.Fatalf("unexpected summary: %v", records[3])
+	}
+
+	if n := len(readJSONLines(t, full)); n != 11 {
+		t.Fatalf("unlimited output should receive all records and summary, got %d", n)
+	}
+}
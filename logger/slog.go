@@ -0,0 +1,520 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// slogLevelFatal Fatal 在 slog 中对应的级别（slog 没有内置 Fatal）
+const slogLevelFatal = slog.LevelError + 4
+
+// toSlogLevel 将日志级别转换为 slog 级别
+func toSlogLevel(level Level) slog.Level {
+	switch level {
+	case DebugLevel:
+		return slog.LevelDebug
+	case InfoLevel:
+		return slog.LevelInfo
+	case WarnLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	case FatalLevel:
+		return slogLevelFatal
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// fromSlogLevel 将 slog 级别转换为日志级别，自定义级别向下取整到最近的已知级别
+func fromSlogLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	case level < slogLevelFatal:
+		return ErrorLevel
+	default:
+		return FatalLevel
+	}
+}
+
+// slogHandler 将 slog 日志写入 kit logger 的 slog.Handler 实现
+type slogHandler struct {
+	logger Logger
+	groups []string      // WithGroup 打开的分组
+	attrs  [][]slog.Attr // attrs[0] 为顶层属性，attrs[i] 为第 i 个分组内的属性
+}
+
+// NewSlogHandler 返回写入 l 的 slog.Handler，日志会经过 l 的级别、输出和 trace 集成
+//
+//	slog.SetDefault(slog.New(logger.NewSlogHandler(logger.Default())))
+//
+// slog 的分组会映射为嵌套对象；高于 Error 的级别按 Error 记录，不会终止程序。
+func NewSlogHandler(l Logger) slog.Handler {
+	return &slogHandler{logger: l, attrs: make([][]slog.Attr, 1)}
+}
+
+// Enabled 实现 slog.Handler
+// 请求级缓冲会收集低于 logger 级别的日志，此时同样返回 true
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	lvl := handlerLevel(level)
+	if zl, ok := h.logger.(*zapLogger); ok {
+		if b := bufferFromContext(ctx); b != nil && b.collects(lvl) {
+			return true
+		}
+		return zl.logger.Core().Enabled(zapLevel(lvl))
+	}
+	return h.logger.GetLevel() <= lvl
+}
+
+// Handle 实现 slog.Handler
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	level := handlerLevel(r.Level)
+
+	var recordAttrs []slog.Attr
+	r.Attrs(func(a slog.Attr) bool {
+		recordAttrs = append(recordAttrs, a)
+		return true
+	})
+	attrs := h.buildAttrs(recordAttrs)
+
+	zl, ok := h.logger.(*zapLogger)
+	if !ok {
+		fields := make(map[string]any, len(attrs))
+		for _, a := range attrs {
+			addAttrToMap(fields, a)
+		}
+		switch level {
+		case DebugLevel:
+			h.logger.DebugMap(ctx, r.Message, fields)
+		case InfoLevel:
+			h.logger.InfoMap(ctx, r.Message, fields)
+		case WarnLevel:
+			h.logger.WarnMap(ctx, r.Message, fields)
+		default:
+			h.logger.ErrorMap(ctx, r.Message, fields)
+		}
+		return nil
+	}
+
+	if zl.opts.enableTrace {
+		if level >= ErrorLevel {
+			markSpanError(ctx, r.Message)
+		}
+		addSpanEvent(ctx, level, r.Message)
+	}
+
+	ent := zapcore.Entry{
+		Level:      zapLevel(level),
+		Time:       r.Time,
+		LoggerName: zl.logger.Name(),
+		Message:    r.Message,
+	}
+	if ent.Time.IsZero() {
+		ent.Time = time.Now()
+	}
+	if zl.opts.caller && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ent.Caller = zapcore.EntryCaller{
+			Defined:  true,
+			PC:       frame.PC,
+			File:     frame.File,
+			Line:     frame.Line,
+			Function: frame.Function,
+		}
+	}
+
+	if b := bufferFromContext(ctx); b != nil && b.handles(level) {
+		zl.bufferEntry(ctx, b, ent, attrsToFields(attrs))
+		return nil
+	}
+
+	core := zl.logger.Core()
+	ce := core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+	ce.Write(zl.withContextFields(ctx, attrsToFields(attrs))...)
+	return nil
+}
+
+// attrsToFields 将 slog 属性转换为 zap 字段
+func attrsToFields(attrs []slog.Attr) []zap.Field {
+	fields := make([]zap.Field, 0, len(attrs))
+	for _, a := range attrs {
+		if f, ok := attrToField(a); ok {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := h.clone()
+	last := len(h2.attrs) - 1
+	h2.attrs[last] = append(append([]slog.Attr(nil), h2.attrs[last]...), attrs...)
+	return h2
+}
+
+// WithGroup 实现 slog.Handler
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := h.clone()
+	h2.groups = append(h2.groups, name)
+	h2.attrs = append(h2.attrs, nil)
+	return h2
+}
+
+// clone 复制 handler，切片按需重新分配，避免子 handler 之间互相影响
+func (h *slogHandler) clone() *slogHandler {
+	return &slogHandler{
+		logger: h.logger,
+		groups: append([]string(nil), h.groups...),
+		attrs:  append([][]slog.Attr(nil), h.attrs...),
+	}
+}
+
+// buildAttrs 将 handler 上的属性、分组与记录属性组合为顶层属性列表
+// 没有任何属性的分组会被省略，与 slog 的约定一致
+func (h *slogHandler) buildAttrs(recordAttrs []slog.Attr) []slog.Attr {
+	inner := recordAttrs
+	for i := len(h.groups); i > 0; i-- {
+		level := append(append([]slog.Attr(nil), h.attrs[i]...), inner...)
+		if len(level) == 0 {
+			inner = nil
+			continue
+		}
+		inner = []slog.Attr{{Key: h.groups[i-1], Value: slog.GroupValue(level...)}}
+	}
+	return append(append([]slog.Attr(nil), h.attrs[0]...), inner...)
+}
+
+// handlerLevel 将 slog 级别转换为 handler 使用的级别，Fatal 降级为 Error
+func handlerLevel(level slog.Level) Level {
+	lvl := fromSlogLevel(level)
+	if lvl == FatalLevel {
+		return ErrorLevel
+	}
+	return lvl
+}
+
+// attrToField 将 slog.Attr 转换为 zap.Field，空属性返回 false
+func attrToField(a slog.Attr) (zap.Field, bool) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return zap.Field{}, false
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return zap.String(a.Key, a.Value.String()), true
+	case slog.KindInt64:
+		return zap.Int64(a.Key, a.Value.Int64()), true
+	case slog.KindUint64:
+		return zap.Uint64(a.Key, a.Value.Uint64()), true
+	case slog.KindFloat64:
+		return zap.Float64(a.Key, a.Value.Float64()), true
+	case slog.KindBool:
+		return zap.Bool(a.Key, a.Value.Bool()), true
+	case slog.KindDuration:
+		return zap.Duration(a.Key, a.Value.Duration()), true
+	case slog.KindTime:
+		return zap.Time(a.Key, a.Value.Time()), true
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return zap.Field{}, false
+		}
+		if a.Key == "" {
+			// 空 key 的分组内联到上层
+			return zap.Inline(slogGroup(attrs)), true
+		}
+		return zap.Object(a.Key, slogGroup(attrs)), true
+	default:
+		if err, ok := a.Value.Any().(error); ok {
+			return zap.NamedError(a.Key, err), true
+		}
+		return zap.Any(a.Key, a.Value.Any()), true
+	}
+}
+
+// slogGroup 将 slog 分组编码为 zap 对象
+type slogGroup []slog.Attr
+
+// MarshalLogObject 实现 zapcore.ObjectMarshaler
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, a := range g {
+		if f, ok := attrToField(a); ok {
+			f.AddTo(enc)
+		}
+	}
+	return nil
+}
+
+// addAttrToMap 将 slog.Attr 写入 map，分组转换为嵌套 map
+func addAttrToMap(m map[string]any, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		m[a.Key] = a.Value.Any()
+		return
+	}
+
+	attrs := a.Value.Group()
+	if len(attrs) == 0 {
+		return
+	}
+	target := m
+	if a.Key != "" {
+		target = make(map[string]any, len(attrs))
+		m[a.Key] = target
+	}
+	for _, ga := range attrs {
+		addAttrToMap(target, ga)
+	}
+}
+
+// slogLogger 基于 slog.Logger 的 Logger 实现
+type slogLogger struct {
+	logger *slog.Logger
+	level  *slog.LevelVar // 与子 logger 共享，叠加在 handler 自身的级别之上
+	name   string
+}
+
+// FromSlog 返回由 slog.Logger 支撑的 Logger，可以将 kit 的日志接口接到任意 slog.Handler
+//
+// Fatal 以 slog.LevelError+4 记录后通过 Exit 终止程序（调用退出回调并刷新默认 logger）；Named 通过 logger 属性标记模块名，
+// 级别由 SetLevel 统一控制，不支持模块级别覆盖。
+func FromSlog(l *slog.Logger) Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelDebug)
+	return &slogLogger{logger: l, level: level}
+}
+
+// Debug 记录 debug 级别日志
+func (l *slogLogger) Debug(ctx context.Context, msg string, fields ...any) {
+	l.log(ctx, DebugLevel, msg, fields...)
+}
+
+// Info 记录 info 级别日志
+func (l *slogLogger) Info(ctx context.Context, msg string, fields ...any) {
+	l.log(ctx, InfoLevel, msg, fields...)
+}
+
+// Warn 记录 warn 级别日志
+func (l *slogLogger) Warn(ctx context.Context, msg string, fields ...any) {
+	l.log(ctx, WarnLevel, msg, fields...)
+}
+
+// Error 记录 error 级别日志
+func (l *slogLogger) Error(ctx context.Context, msg string, fields ...any) {
+	l.log(ctx, ErrorLevel, msg, fields...)
+}
+
+// Fatal 记录 fatal 级别日志并终止程序
+func (l *slogLogger) Fatal(ctx context.Context, msg string, fields ...any) {
+	l.log(ctx, FatalLevel, msg, fields...)
+}
+
+// DebugMap 记录 debug 级别日志（map 字段）
+func (l *slogLogger) DebugMap(ctx context.Context, msg string, fields map[string]any) {
+	l.log(ctx, DebugLevel, msg, fields)
+}
+
+// InfoMap 记录 info 级别日志（map 字段）
+func (l *slogLogger) InfoMap(ctx context.Context, msg string, fields map[string]any) {
+	l.log(ctx, InfoLevel, msg, fields)
+}
+
+// WarnMap 记录 warn 级别日志（map 字段）
+func (l *slogLogger) WarnMap(ctx context.Context, msg string, fields map[string]any) {
+	l.log(ctx, WarnLevel, msg, fields)
+}
+
+// ErrorMap 记录 error 级别日志（map 字段）
+func (l *slogLogger) ErrorMap(ctx context.Context, msg string, fields map[string]any) {
+	l.log(ctx, ErrorLevel, msg, fields)
+}
+
+// FatalMap 记录 fatal 级别日志（map 字段）并终止程序
+func (l *slogLogger) FatalMap(ctx context.Context, msg string, fields map[string]any) {
+	l.log(ctx, FatalLevel, msg, fields)
+}
+
+// DebugFields 记录 debug 级别日志（强类型字段）
+func (l *slogLogger) DebugFields(ctx context.Context, msg string, fields ...Field) {
+	l.logFields(ctx, DebugLevel, msg, fields)
+}
+
+// InfoFields 记录 info 级别日志（强类型字段）
+func (l *slogLogger) InfoFields(ctx context.Context, msg string, fields ...Field) {
+	l.logFields(ctx, InfoLevel, msg, fields)
+}
+
+// WarnFields 记录 warn 级别日志（强类型字段）
+func (l *slogLogger) WarnFields(ctx context.Context, msg string, fields ...Field) {
+	l.logFields(ctx, WarnLevel, msg, fields)
+}
+
+// ErrorFields 记录 error 级别日志（强类型字段）
+func (l *slogLogger) ErrorFields(ctx context.Context, msg string, fields ...Field) {
+	l.logFields(ctx, ErrorLevel, msg, fields)
+}
+
+// FatalFields 记录 fatal 级别日志（强类型字段）并终止程序
+func (l *slogLogger) FatalFields(ctx context.Context, msg string, fields ...Field) {
+	l.logFields(ctx, FatalLevel, msg, fields)
+}
+
+// With 创建带预设字段的子 logger
+func (l *slogLogger) With(fields ...any) Logger {
+	return &slogLogger{logger: l.logger.With(slogArgs(fields)...), level: l.level, name: l.name}
+}
+
+// Named 创建模块 logger，模块名记录在 logger 属性中
+func (l *slogLogger) Named(name string) Logger {
+	if name == "" {
+		return l
+	}
+	full := name
+	if l.name != "" {
+		full = l.name + "." + name
+	}
+	return &slogLogger{logger: l.logger.With(slog.String("logger", full)), level: l.level, name: full}
+}
+
+// SetLevel 运行时调整日志级别
+func (l *slogLogger) SetLevel(level Level) {
+	l.level.Set(toSlogLevel(level))
+}
+
+// GetLevel 返回当前生效的日志级别：SetLevel 设置的级别与 handler 自身最低启用级别中较高的一个
+func (l *slogLogger) GetLevel() Level {
+	current := fromSlogLevel(l.level.Level())
+	for _, level := range []Level{DebugLevel, InfoLevel, WarnLevel, ErrorLevel} {
+		if level < current {
+			continue
+		}
+		if l.logger.Enabled(context.Background(), toSlogLevel(level)) {
+			return level
+		}
+	}
+	return FatalLevel
+}
+
+// Sync slog 没有缓冲区概念，直接返回
+func (l *slogLogger) Sync() error {
+	return nil
+}
+
+// log 内部日志记录方法（结构化字段）
+// 调用深度需与 Info 等方法保持一致（业务代码 -> Info -> log -> output）
+func (l *slogLogger) log(ctx context.Context, level Level, msg string, fields ...any) {
+	l.output(ctx, level, msg, fields)
+}
+
+// logFields 内部日志记录方法（强类型字段）
+func (l *slogLogger) logFields(ctx context.Context, level Level, msg string, fields []Field) {
+	l.output(ctx, level, msg, []any{fields})
+}
+
+// output 构造 slog.Record 并交给 handler，调用者信息指向业务代码；Fatal 级别记录后终止程序
+func (l *slogLogger) output(ctx context.Context, level Level, msg string, fields []any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	slogLevel := toSlogLevel(level)
+	if slogLevel >= l.level.Level() && l.logger.Enabled(ctx, slogLevel) {
+		// 跳过 runtime.Callers、output、log 和 Debug/Info 等方法
+		var pcs [1]uintptr
+		runtime.Callers(4, pcs[:])
+
+		r := slog.NewRecord(time.Now(), slogLevel, msg, pcs[0])
+		r.Add(slogArgs(fields)...)
+		_ = l.logger.Handler().Handle(ctx, r)
+	}
+
+	if level == FatalLevel {
+		// 与 zap 实现一致，调用退出回调并刷新默认 logger 后退出
+		Exit(1)
+	}
+}
+
+// slogArgs 将日志参数转换为 slog 参数：Field 转为 slog.Attr，map 按 key 排序展开，error 使用 error 作为 key
+func slogArgs(fields []any) []any {
+	var args []any
+	for i := 0; i < len(fields); i++ {
+		switch v := fields[i].(type) {
+		case Field:
+			args = append(args, fieldToAttr(v))
+		case []Field:
+			for _, f := range v {
+				args = append(args, fieldToAttr(f))
+			}
+		case map[string]any:
+			args = append(args, mapToArgs(v)...)
+		case error:
+			args = append(args, slog.Any("error", v))
+		case string:
+			// key-value 对原样交给 slog 解析
+			args = append(args, v)
+			if i+1 < len(fields) {
+				args = append(args, fields[i+1])
+				i++
+			}
+		default:
+			args = append(args, v)
+		}
+	}
+	return args
+}
+
+// fieldToAttr 将 Field 转换为 slog.Attr
+func fieldToAttr(f Field) slog.Attr {
+	enc := zapcore.NewMapObjectEncoder()
+	f.field.AddTo(enc)
+	if v, ok := enc.Fields[f.field.Key]; ok {
+		return slog.Any(f.field.Key, v)
+	}
+	return slog.Attr{}
+}
+
+// mapToArgs 将 map 字段按 key 排序后转换为 slog 参数
+func mapToArgs(fields map[string]any) []any {
+	if len(fields) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	args := make([]any, 0, len(keys))
+	for _, k := range keys {
+		args = append(args, slog.Any(k, fields[k]))
+	}
+	return args
+}
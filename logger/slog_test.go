@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSlogHandler(t *testing.T) {
+	l, logs := newObservedLogger(t, WithLevel(InfoLevel))
+	l.opts.caller = true
+	sl := slog.New(NewSlogHandler(l.Named("lib")))
+
+	sl.Debug("dropped")
+	sl.With("a", 1).WithGroup("req").With("method", "GET").Info("served", "status", 200, slog.Group("user", "id", 7))
+	sl.WithGroup("empty").Warn("no attrs")
+	sl.Log(context.Background(), slog.LevelError+4, "fatal from slog")
+
+	entries := logs.TakeAll()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	e := entries[0]
+	if e.LoggerName != "lib" || e.Level != zapcore.InfoLevel {
+		t.Fatalf("unexpected entry: %+v", e.Entry)
+	}
+	if filepath.Base(e.Caller.File) != "slog_test.go" {
+		t.Fatalf("caller should point to slog call site, got %s", e.Caller.File)
+	}
+	fields := e.ContextMap()
+	req, ok := fields["req"].(map[string]any)
+	if !ok || fields["a"] != int64(1) || req["method"] != "GET" || req["status"] != int64(200) {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+	if user, ok := req["user"].(map[string]any); !ok || user["id"] != int64(7) {
+		t.Fatalf("unexpected nested group: %v", req["user"])
+	}
+
+	if len(entries[1].Context) != 0 {
+		t.Fatalf("empty group should be omitted: %v", entries[1].Context)
+	}
+	if entries[2].Level != zapcore.ErrorLevel {
+		t.Fatalf("levels above error should map to error, got %s", entries[2].Level)
+	}
+}
+
+func TestSlogHandlerBuffer(t *testing.T) {
+	l, logs := newObservedLogger(t, WithLevel(WarnLevel))
+	l.opts.caller = true
+	sl := slog.New(NewSlogHandler(l))
+
+	ctx, buf := BeginBuffer(context.Background())
+	sl.DebugContext(ctx, "step 1", "k", "v")
+	sl.WarnContext(ctx, "not buffered")
+	if buf.Len() != 1 || logs.Len() != 1 {
+		t.Fatalf("expected 1 buffered and 1 written, got %d and %d", buf.Len(), logs.Len())
+	}
+
+	buf.Flush()
+	entries := logs.TakeAll()
+	if len(entries) != 2 || entries[1].Message != "step 1" || entries[1].ContextMap()["k"] != "v" {
+		t.Fatalf("unexpected entries after flush: %+v", entries)
+	}
+	if filepath.Base(entries[1].Caller.File) != "slog_test.go" {
+		t.Fatalf("buffered caller should point to slog call site, got %s", entries[1].Caller.File)
+	}
+}
+
+func TestFromSlog(t *testing.T) {
+	var buf bytes.Buffer
+	l := FromSlog(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	if got := l.GetLevel(); got != InfoLevel {
+		t.Fatalf("expected level from handler, got %s", got)
+	}
+
+	ctx := context.Background()
+	l.Debug(ctx, "dropped")
+	l.Named("orm").With("table", "users").Warn(ctx, "slow query", "ms", 120)
+	l.SetLevel(ErrorLevel)
+	l.InfoMap(ctx, "dropped by SetLevel", map[string]any{"k": "v"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line, got %d: %s", len(lines), buf.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec["level"] != "WARN" || rec["logger"] != "orm" || rec["table"] != "users" || rec["ms"] != float64(120) {
+		t.Fatalf("unexpected record: %v", rec)
+	}
+	if l.GetLevel() != ErrorLevel {
+		t.Fatalf("expected error level, got %s", l.GetLevel())
+	}
+}
+
+func TestFromSlogFatalRunsExitHandlers(t *testing.T) {
+	code := stubExit(t)
+	var buf bytes.Buffer
+	l := FromSlog(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	var called bool
+	RegisterExitHandler(func() { called = true })
+	l.Fatal(context.Background(), "boom")
+
+	if *code != 1 || !called {
+		t.Errorf("exit code = %d, handler called = %v", *code, called)
+	}
+	if !strings.Contains(buf.String(), `"msg":"boom"`) {
+		t.Errorf("fatal entry not written: %s", buf.String())
+	}
+}
+
+func TestFromSlogFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := FromSlog(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true})))
+	l.InfoFields(context.Background(), "typed", String("s", "v"), Int("i", 1))
+
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec["s"] != "v" || rec["i"] != float64(1) {
+		t.Errorf("record = %v", rec)
+	}
+	if src, _ := rec["source"].(map[string]any); !strings.HasSuffix(fmt.Sprint(src["file"]), "slog_test.go") {
+		t.Errorf("source = %v", rec["source"])
+	}
+}
@@ -2,7 +2,10 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -11,28 +14,82 @@ import (
 // zapLogger 基于 zap 的 Logger 实现
 type zapLogger struct {
 	logger *zap.Logger
-	sugar  *zap.SugaredLogger
 	opts   *options
+	level  zap.AtomicLevel // 与子 logger 共享，支持运行时调整
+
+	name     string         // 模块名，根 logger 为空
+	modules  *moduleLevels  // 模块级别规则，整棵 logger 树共享
+	drops    *dropCounter   // 采样和限流的丢弃统计，整棵 logger 树共享
+	otlp     []*otlpSyncer  // OTLP 导出器，整棵 logger 树共享
+	rotators []*fileRotator // 文件切割器，整棵 logger 树共享
+	hooks    []*hookCore    // hook 处理协程，整棵 logger 树共享
+	fatal    *fatalHook     // Fatal 处理，整棵 logger 树共享
 }
 
 // New 创建新的 logger 实例
 func New(opts ...Option) (Logger, error) {
 	options := newOptions(opts...)
+	if options.err != nil {
+		return nil, options.err
+	}
+	level := zap.NewAtomicLevelAt(zapLevel(options.level))
+	modules := newModuleLevels(options.moduleLevels)
+
+	drops := newDropCounter(options.dropSummaryInterval)
+	rt := &outputRuntime{drops: drops}
 
-	// 创建所有输出的 cores
-	cores, err := createCores(options)
+	// 创建所有输出的 cores，级别统一由外层的 levelFilterCore 控制
+	cores, err := createCores(options, rt)
 	if err != nil {
+		rt.close()
 		return nil, fmt.Errorf("failed to create cores: %w", err)
 	}
 
-	// 组合所有 cores
+	// 丢弃统计直接写入各输出，不经过采样和限流
+	summaryCores := make([]zapcore.Core, len(cores))
+	for i, c := range cores {
+		summaryCores[i] = unwrapRateLimit(c)
+	}
+	drops.core = zapcore.NewTee(summaryCores...)
+
+	// hook 与输出并列，丢弃统计汇总日志不经过 hook
+	hooks := make([]*hookCore, 0, len(options.hooks))
+	for _, h := range options.hooks {
+		hc := newHookCore(h, drops)
+		hooks = append(hooks, hc)
+		cores = append(cores, hc)
+	}
+
+	// 组合所有 cores，按需启用采样（按级别 + 消息统计）
 	core := zapcore.NewTee(cores...)
+	if options.redactor != nil {
+		core = &redactCore{Core: core, r: options.redactor}
+	}
+	if options.sampling != nil {
+		core = zapcore.NewSamplerWithOptions(core, options.sampling.tick,
+			options.sampling.initial, options.sampling.thereafter, drops.samplerHook())
+	}
+	core = newLevelFilterCore(core, newModuleEnabler("", modules, level))
+
+	// Fatal 日志先调用退出回调、刷新全部输出，再退出进程
+	fatal := &fatalHook{
+		core:     core,
+		drops:    drops,
+		otlp:     rt.otlp,
+		rotators: rt.rotators,
+		timeout:  options.exitTimeout,
+		panics:   options.fatalPanic,
+	}
+	if fatal.timeout <= 0 {
+		fatal.timeout = defaultExitTimeout
+	}
 
 	// 创建 zap logger 选项
-	zapOpts := []zap.Option{}
+	zapOpts := []zap.Option{zap.WithFatalHook(fatal)}
 
 	if options.caller {
-		zapOpts = append(zapOpts, zap.AddCaller(), zap.AddCallerSkip(1))
+		// 跳过 Info 等方法和内部的 log 方法，指向业务调用位置
+		zapOpts = append(zapOpts, zap.AddCaller(), zap.AddCallerSkip(2))
 	}
 
 	if options.stacktrace {
@@ -46,11 +103,24 @@ func New(opts ...Option) (Logger, error) {
 	// 创建 zap.Logger
 	zlog := zap.New(core, zapOpts...)
 
-	return &zapLogger{
-		logger: zlog,
-		sugar:  zlog.Sugar(),
-		opts:   options,
-	}, nil
+	l := &zapLogger{
+		logger:   zlog,
+		opts:     options,
+		level:    level,
+		modules:  modules,
+		drops:    drops,
+		otlp:     rt.otlp,
+		rotators: rt.rotators,
+		hooks:    hooks,
+		fatal:    fatal,
+	}
+
+	// 绑定外部级别来源（如配置中心）
+	if options.levelLookup != nil {
+		BindLevel(l, options.levelLookup, options.levelSubscribe)
+	}
+
+	return l, nil
 }
 
 // Debug 记录 debug 级别日志
@@ -70,19 +140,11 @@ func (l *zapLogger) Warn(ctx context.Context, msg string, fields ...any) {
 
 // Error 记录 error 级别日志
 func (l *zapLogger) Error(ctx context.Context, msg string, fields ...any) {
-	// Error 日志需要标记 span 为 error
-	if l.opts.enableTrace {
-		markSpanError(ctx, msg)
-	}
 	l.log(ctx, ErrorLevel, msg, fields...)
 }
 
 // Fatal 记录 fatal 级别日志
 func (l *zapLogger) Fatal(ctx context.Context, msg string, fields ...any) {
-	// Fatal 日志也需要标记 span 为 error
-	if l.opts.enableTrace {
-		markSpanError(ctx, msg)
-	}
 	l.log(ctx, FatalLevel, msg, fields...)
 }
 
@@ -103,107 +165,246 @@ func (l *zapLogger) WarnMap(ctx context.Context, msg string, fields map[string]a
 
 // ErrorMap 记录 error 级别日志（map 字段）
 func (l *zapLogger) ErrorMap(ctx context.Context, msg string, fields map[string]any) {
-	if l.opts.enableTrace {
-		markSpanError(ctx, msg)
-	}
 	l.logMap(ctx, ErrorLevel, msg, fields)
 }
 
 // FatalMap 记录 fatal 级别日志（map 字段）
 func (l *zapLogger) FatalMap(ctx context.Context, msg string, fields map[string]any) {
-	if l.opts.enableTrace {
-		markSpanError(ctx, msg)
-	}
 	l.logMap(ctx, FatalLevel, msg, fields)
 }
 
+// DebugFields 记录 debug 级别日志（强类型字段）
+func (l *zapLogger) DebugFields(ctx context.Context, msg string, fields ...Field) {
+	l.logFields(ctx, DebugLevel, msg, fields)
+}
+
+// InfoFields 记录 info 级别日志（强类型字段）
+func (l *zapLogger) InfoFields(ctx context.Context, msg string, fields ...Field) {
+	l.logFields(ctx, InfoLevel, msg, fields)
+}
+
+// WarnFields 记录 warn 级别日志（强类型字段）
+func (l *zapLogger) WarnFields(ctx context.Context, msg string, fields ...Field) {
+	l.logFields(ctx, WarnLevel, msg, fields)
+}
+
+// ErrorFields 记录 error 级别日志（强类型字段）
+func (l *zapLogger) ErrorFields(ctx context.Context, msg string, fields ...Field) {
+	l.logFields(ctx, ErrorLevel, msg, fields)
+}
+
+// FatalFields 记录 fatal 级别日志（强类型字段）
+func (l *zapLogger) FatalFields(ctx context.Context, msg string, fields ...Field) {
+	l.logFields(ctx, FatalLevel, msg, fields)
+}
+
 // With 创建带预设字段的子 logger
 func (l *zapLogger) With(fields ...any) Logger {
 	return &zapLogger{
-		logger: l.logger.With(l.convertToZapFields(fields...)...),
-		sugar:  l.sugar.With(fields...),
-		opts:   l.opts,
+		logger:   l.logger.With(convertToZapFields(fields...)...),
+		opts:     l.opts,
+		level:    l.level,
+		name:     l.name,
+		modules:  l.modules,
+		drops:    l.drops,
+		otlp:     l.otlp,
+		rotators: l.rotators,
+		hooks:    l.hooks,
+		fatal:    l.fatal,
 	}
 }
 
-// Sync 刷新缓冲区
+// Named 创建模块 logger，名称以 "." 与父模块名拼接
+func (l *zapLogger) Named(name string) Logger {
+	if name == "" {
+		return l
+	}
+
+	full := name
+	if l.name != "" {
+		full = l.name + "." + name
+	}
+
+	enabler := newModuleEnabler(full, l.modules, l.level)
+	zlog := l.logger.Named(name).WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newLevelFilterCore(core, enabler)
+	}))
+
+	return &zapLogger{
+		logger:   zlog,
+		opts:     l.opts,
+		level:    l.level,
+		name:     full,
+		modules:  l.modules,
+		drops:    l.drops,
+		otlp:     l.otlp,
+		rotators: l.rotators,
+		hooks:    l.hooks,
+		fatal:    l.fatal,
+	}
+}
+
+// SetLevel 运行时调整日志级别
+// 根 logger 调整全局级别；模块 logger 为自身模块设置级别覆盖
+func (l *zapLogger) SetLevel(level Level) {
+	if l.name != "" {
+		l.modules.set(l.name, level)
+		return
+	}
+	l.level.SetLevel(zapLevel(level))
+}
+
+// GetLevel 返回当前生效的日志级别
+func (l *zapLogger) GetLevel() Level {
+	if l.name != "" {
+		if level, ok := l.modules.lookup(l.name); ok {
+			return level
+		}
+	}
+	return fromZapLevel(l.level.Level())
+}
+
+// SetModuleLevel 为匹配 pattern 的模块设置级别覆盖
+func (l *zapLogger) SetModuleLevel(pattern string, level Level) {
+	l.modules.set(pattern, level)
+}
+
+// SetModuleLevels 用 spec（如 "orm.*=warn,http=debug"）整体替换模块级别规则，空字符串清空所有规则
+func (l *zapLogger) SetModuleLevels(spec string) error {
+	rules, err := ParseModuleLevels(spec)
+	if err != nil {
+		return err
+	}
+	l.modules.replace(rules)
+	return nil
+}
+
+// ModuleLevels 返回当前的模块级别规则
+func (l *zapLogger) ModuleLevels() map[string]Level {
+	return l.modules.snapshot()
+}
+
+// Sync 刷新缓冲区，未输出的丢弃统计会先写出
 func (l *zapLogger) Sync() error {
+	l.drops.flush()
 	return l.logger.Sync()
 }
 
+// Close 刷新全部输出，停止 hook 处理协程，关闭 OTLP 导出器和文件切割器，作用于整棵 logger 树
+func (l *zapLogger) Close() error {
+	errs := []error{l.Sync()}
+	for _, h := range l.hooks {
+		errs = append(errs, h.close())
+	}
+	for _, s := range l.otlp {
+		errs = append(errs, s.Close())
+	}
+	for _, r := range l.rotators {
+		errs = append(errs, r.Close())
+	}
+	return errors.Join(errs...)
+}
+
 // log 内部日志记录方法（结构化字段）
+// 调用深度需与 Info 等方法保持一致（业务代码 -> Info -> log），以保证 caller 正确
 func (l *zapLogger) log(ctx context.Context, level Level, msg string, fields ...any) {
-	// 添加 trace 信息
-	var zapFields []zap.Field
-	if l.opts.enableTrace {
-		zapFields = extractTraceFields(ctx)
-		addSpanEvent(ctx, level, msg)
+	l.traceEvent(ctx, level, msg)
+	if b := bufferFromContext(ctx); b != nil && l.bufferLog(ctx, b, level, msg, fields) {
+		return
 	}
 
-	// 转换字段为 zap.Field
-	zapFields = append(zapFields, l.convertToZapFields(fields...)...)
+	ce := l.logger.Check(zapLevel(level), msg)
+	if ce == nil {
+		return
+	}
 
-	// 记录日志
-	switch level {
-	case DebugLevel:
-		l.logger.Debug(msg, zapFields...)
-	case InfoLevel:
-		l.logger.Info(msg, zapFields...)
-	case WarnLevel:
-		l.logger.Warn(msg, zapFields...)
-	case ErrorLevel:
-		l.logger.Error(msg, zapFields...)
-	case FatalLevel:
-		l.logger.Fatal(msg, zapFields...)
+	// 转换字段为 zap.Field，并合并 context 中的字段
+	zapFields, problems := parseFields(fields)
+	if len(problems) > 0 && l.opts.strictFields {
+		l.reportMalformedFields(msg, problems)
 	}
+	ce.Write(l.withContextFields(ctx, zapFields)...)
 }
 
 // logMap 内部日志记录方法（map 字段）
 func (l *zapLogger) logMap(ctx context.Context, level Level, msg string, fields map[string]any) {
-	// 添加 trace 信息
-	var zapFields []zap.Field
-	if l.opts.enableTrace {
-		zapFields = extractTraceFields(ctx)
-		addSpanEvent(ctx, level, msg)
+	l.traceEvent(ctx, level, msg)
+	if b := bufferFromContext(ctx); b != nil && l.bufferLog(ctx, b, level, msg, []any{fields}) {
+		return
+	}
+
+	ce := l.logger.Check(zapLevel(level), msg)
+	if ce == nil {
+		return
 	}
 
 	// 转换 map 为 zap.Field
-	for k, v := range fields {
-		zapFields = append(zapFields, zap.Any(k, v))
+	zapFields := appendMapFields(make([]zap.Field, 0, len(fields)), fields)
+	ce.Write(l.withContextFields(ctx, zapFields)...)
+}
+
+// logFields 内部日志记录方法（强类型字段）
+// 字段直接作为 zap.Field 写入，不经过 parseFields，记录的字段数量不影响内存分配次数
+func (l *zapLogger) logFields(ctx context.Context, level Level, msg string, fields []Field) {
+	l.traceEvent(ctx, level, msg)
+	if b := bufferFromContext(ctx); b != nil && l.bufferLog(ctx, b, level, msg, []any{fields}) {
+		return
 	}
 
-	// 记录日志
-	switch level {
-	case DebugLevel:
-		l.logger.Debug(msg, zapFields...)
-	case InfoLevel:
-		l.logger.Info(msg, zapFields...)
-	case WarnLevel:
-		l.logger.Warn(msg, zapFields...)
-	case ErrorLevel:
-		l.logger.Error(msg, zapFields...)
-	case FatalLevel:
-		l.logger.Fatal(msg, zapFields...)
+	ce := l.logger.Check(zapLevel(level), msg)
+	if ce == nil {
+		return
 	}
+	ce.Write(l.withContextFields(ctx, asZapFields(fields))...)
 }
 
-// convertToZapFields 转换字段为 zap.Field
-func (l *zapLogger) convertToZapFields(fields ...any) []zap.Field {
-	if len(fields) == 0 {
-		return nil
+// traceEvent 将日志记录到当前 span，Error 及以上级别同时标记 span 为 error
+func (l *zapLogger) traceEvent(ctx context.Context, level Level, msg string) {
+	if !l.opts.enableTrace {
+		return
 	}
+	if level >= ErrorLevel {
+		markSpanError(ctx, msg)
+	}
+	addSpanEvent(ctx, level, msg)
+}
 
-	var zapFields []zap.Field
-
-	// 如果字段是偶数，尝试解析为 key-value 对
-	if len(fields)%2 == 0 {
-		for i := 0; i < len(fields); i += 2 {
-			if key, ok := fields[i].(string); ok {
-				zapFields = append(zapFields, zap.Any(key, fields[i+1]))
-			}
-		}
+// reportMalformedFields 严格模式下报告格式错误的字段，附带业务代码的调用位置
+// 仅由 log 调用，调用链为 业务代码 -> Info -> log -> reportMalformedFields
+func (l *zapLogger) reportMalformedFields(msg string, problems []string) {
+	ent := zapcore.Entry{
+		Level:      zapcore.WarnLevel,
+		Time:       time.Now(),
+		LoggerName: l.logger.Name(),
+		Message:    "malformed log fields",
+	}
+	if pc, file, line, ok := runtime.Caller(3); ok {
+		ent.Caller = zapcore.NewEntryCaller(pc, file, line, true)
 	}
 
-	return zapFields
+	if ce := l.logger.Core().Check(ent, nil); ce != nil {
+		ce.Write(
+			zap.String("log_message", msg),
+			zap.Strings("problems", problems),
+			zap.String("call_site", ent.Caller.String()),
+		)
+	}
 }
 
+// withContextFields 在调用方字段前加入 trace 信息和 context 携带的字段
+// 同名字段以调用方传入的为准
+func (l *zapLogger) withContextFields(ctx context.Context, fields []zap.Field) []zap.Field {
+	var ctxFields []zap.Field
+	if l.opts.enableTrace {
+		ctxFields = extractTraceFields(ctx)
+	}
+	ctxFields = append(ctxFields, extractContextFields(ctx)...)
+
+	if len(ctxFields) > 0 {
+		fields = append(mergeFields(ctxFields, fields), fields...)
+	}
+	if len(l.opts.hooks) > 0 {
+		fields = append(fields, hookContextField(ctx))
+	}
+	return fields
+}
@@ -3,6 +3,7 @@ package orm
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/Si40Code/kit/logger"
@@ -13,48 +14,72 @@ import (
 // gormLogger GORM 日志适配器，桥接到 kit/logger
 type gormLogger struct {
 	logger               logger.Logger
+	level                gormlogger.LogLevel
 	slowThreshold        time.Duration
 	ignoreRecordNotFound bool
 }
 
+// NewGormLogger 返回写入 l 的 GORM 日志适配器，用于自行调用 gorm.Open 的场景：
+//
+//	db, err := gorm.Open(dialector, &gorm.Config{Logger: orm.NewGormLogger(logger.Default())})
+//
+// 支持 WithSlowThreshold 和 WithIgnoreRecordNotFoundError 选项，其他选项忽略。
+// 日志使用调用 GORM 时传入的 context，trace 信息与其他日志一致；默认记录全部 SQL，可通过 LogMode 调整。
+func NewGormLogger(l logger.Logger, opts ...Option) gormlogger.Interface {
+	options := newOptions(opts...)
+	return newGormLogger(l, options.slowThreshold, options.ignoreRecordNotFound)
+}
+
 // newGormLogger 创建新的 GORM 日志适配器
 func newGormLogger(l logger.Logger, slowThreshold time.Duration, ignoreRecordNotFound bool) *gormLogger {
 	return &gormLogger{
 		logger:               l,
+		level:                gormlogger.Info,
 		slowThreshold:        slowThreshold,
 		ignoreRecordNotFound: ignoreRecordNotFound,
 	}
 }
 
-// LogMode 实现 GORM logger.Interface
+// LogMode 实现 GORM logger.Interface，返回指定级别的副本
+// Silent 不记录；Error 只记录 SQL 错误；Warn 增加慢查询；Info 记录全部 SQL
 func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
-	return l
+	clone := *l
+	clone.level = level
+	return &clone
 }
 
 // Info 记录 Info 级别日志
 func (l *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
-	if l.logger != nil {
-		l.logger.Info(ctx, msg, "data", data)
+	if l.logger != nil && l.level >= gormlogger.Info {
+		l.logger.Info(ctx, formatGormMessage(msg, data))
 	}
 }
 
 // Warn 记录 Warn 级别日志
 func (l *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
-	if l.logger != nil {
-		l.logger.Warn(ctx, msg, "data", data)
+	if l.logger != nil && l.level >= gormlogger.Warn {
+		l.logger.Warn(ctx, formatGormMessage(msg, data))
 	}
 }
 
 // Error 记录 Error 级别日志
 func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
-	if l.logger != nil {
-		l.logger.Error(ctx, msg, "data", data)
+	if l.logger != nil && l.level >= gormlogger.Error {
+		l.logger.Error(ctx, formatGormMessage(msg, data))
+	}
+}
+
+// formatGormMessage GORM 的 Info / Warn / Error 使用 printf 风格的参数
+func formatGormMessage(msg string, data []interface{}) string {
+	if len(data) == 0 {
+		return msg
 	}
+	return fmt.Sprintf(msg, data...)
 }
 
 // Trace 记录 SQL 执行日志（核心方法）
 func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
-	if l.logger == nil {
+	if l.logger == nil || l.level <= gormlogger.Silent {
 		return
 	}
 
@@ -68,17 +93,17 @@ func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql
 	}
 
 	switch {
-	case err != nil && (!errors.Is(err, gorm.ErrRecordNotFound) || !l.ignoreRecordNotFound):
+	case err != nil && l.level >= gormlogger.Error && (!errors.Is(err, gorm.ErrRecordNotFound) || !l.ignoreRecordNotFound):
 		// 错误日志（如果不是 RecordNotFound 或者没有配置忽略）
 		fields["error"] = err.Error()
 		l.logger.ErrorMap(ctx, "database query error", fields)
 
-	case elapsed > l.slowThreshold && l.slowThreshold != 0:
+	case elapsed > l.slowThreshold && l.slowThreshold != 0 && l.level >= gormlogger.Warn:
 		// 慢查询日志
 		fields["slow_threshold_ms"] = l.slowThreshold.Milliseconds()
 		l.logger.WarnMap(ctx, "slow query detected", fields)
 
-	default:
+	case l.level >= gormlogger.Info:
 		// 正常查询日志
 		l.logger.InfoMap(ctx, "database query executed", fields)
 	}
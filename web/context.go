@@ -0,0 +1,48 @@
+package web
+
+import (
+	"context"
+
+	"github.com/Si40Code/kit/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// requestInfoKey 请求信息在 context 中的 key
+type requestInfoKey struct{}
+
+// requestInfo 请求级别的日志信息，由中间件写入请求 context
+type requestInfo struct {
+	clientIP string
+	route    string
+}
+
+func init() {
+	// 使用请求 context 记录的日志自动带上 client_ip 和 route
+	logger.RegisterContextExtractor(requestLogFields)
+}
+
+// requestLogFields 从请求 context 中提取日志字段
+func requestLogFields(ctx context.Context) []any {
+	info, ok := ctx.Value(requestInfoKey{}).(*requestInfo)
+	if !ok {
+		return nil
+	}
+
+	fields := []any{"client_ip", info.clientIP}
+	if info.route != "" {
+		fields = append(fields, "route", info.route)
+	}
+	return fields
+}
+
+// requestContextMiddleware 将请求信息写入 context，供 handler 中的日志使用
+func (s *Server) requestContextMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := &requestInfo{
+			clientIP: c.ClientIP(),
+			route:    c.FullPath(),
+		}
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestInfoKey{}, info))
+		c.Next()
+	}
+}
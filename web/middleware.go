@@ -2,12 +2,14 @@ package web
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/Si40Code/kit/logger"
 	"github.com/gin-gonic/gin"
 )
 
@@ -15,7 +17,7 @@ import (
 func (s *Server) loggingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 检查是否跳过
-		if s.shouldSkipPath(c.Request.URL.Path) {
+		if s.shouldSkipRequest(c) {
 			c.Next()
 			return
 		}
@@ -24,6 +26,14 @@ func (s *Server) loggingMiddleware() gin.HandlerFunc {
 		path := c.Request.URL.Path
 		query := c.Request.URL.RawQuery
 
+		// 开启请求级日志缓冲
+		var logBuf *logger.Buffer
+		if s.options.logBuffering {
+			var ctx context.Context
+			ctx, logBuf = logger.BeginBuffer(c.Request.Context(), s.options.logBufferOptions...)
+			c.Request = c.Request.WithContext(ctx)
+		}
+
 		// 读取请求 body（处理文件上传）
 		var reqBody string
 		if !s.isMultipartForm(c) && c.Request.ContentLength > 0 {
@@ -80,6 +90,15 @@ func (s *Server) loggingMiddleware() gin.HandlerFunc {
 			fields["errors"] = c.Errors.String()
 		}
 
+		// 请求失败或过慢时写出缓冲的日志，否则丢弃（需在访问日志之前结束缓冲）
+		if logBuf != nil {
+			if s.shouldFlushLogBuffer(statusCode, latency, len(c.Errors) > 0) {
+				logBuf.Flush()
+			} else {
+				logBuf.Discard()
+			}
+		}
+
 		// 记录日志
 		if s.options.logger != nil {
 			logLevel := s.determineLogLevel(statusCode, latency)
@@ -152,6 +171,15 @@ func (s *Server) corsMiddleware() gin.HandlerFunc {
 
 // Helper functions
 
+// shouldSkipRequest 是否跳过访问日志：配置的跳过路径，以及日志级别接口的 GET 查询
+func (s *Server) shouldSkipRequest(c *gin.Context) bool {
+	path := c.Request.URL.Path
+	if path == s.options.logLevelPath && c.Request.Method == http.MethodGet {
+		return true
+	}
+	return s.shouldSkipPath(path)
+}
+
 func (s *Server) shouldSkipPath(path string) bool {
 	for _, skip := range s.options.skipPaths {
 		if path == skip {
@@ -201,6 +229,15 @@ func (s *Server) determineLogLevel(statusCode int, latency time.Duration) string
 	return "info"
 }
 
+// shouldFlushLogBuffer 判断是否写出请求缓冲的日志
+func (s *Server) shouldFlushLogBuffer(statusCode int, latency time.Duration, hasErrors bool) bool {
+	threshold := s.options.logBufferThreshold
+	if threshold <= 0 {
+		threshold = s.options.slowRequestThresh
+	}
+	return statusCode >= 500 || hasErrors || latency > threshold
+}
+
 // bodyLogWriter 用于捕获响应 body
 type bodyLogWriter struct {
 	gin.ResponseWriter
@@ -3,6 +3,7 @@ package web
 import (
 	"time"
 
+	"github.com/Si40Code/kit/logger"
 	"github.com/gin-gonic/gin"
 )
 
@@ -47,6 +48,16 @@ type options struct {
 
 	// 文件上传配置
 	maxMultipartMemory int64 // 最大文件内存（字节）
+
+	// 日志级别管理接口
+	logLevelPath        string
+	logLevelLogger      logger.Logger
+	logLevelMiddlewares []gin.HandlerFunc // 仅作用于该接口的中间件（如鉴权）
+
+	// 请求级日志缓冲
+	logBuffering       bool
+	logBufferThreshold time.Duration
+	logBufferOptions   []logger.BufferOption
 }
 
 // newOptions 创建默认配置
@@ -163,3 +174,25 @@ func WithMaxMultipartMemory(size int64) Option {
 		o.maxMultipartMemory = size
 	}
 }
+
+// WithLogLevelEndpoint 注册日志级别管理接口（GET 查询，PUT/POST 调整）
+// middlewares 只作用于该接口，在处理请求前执行，用于鉴权，例如 gin.BasicAuth(accounts)
+// GET 查询不记录访问日志，PUT/POST 调整照常记录，作为级别变更的审计记录
+func WithLogLevelEndpoint(path string, l logger.Logger, middlewares ...gin.HandlerFunc) Option {
+	return func(o *options) {
+		o.logLevelPath = path
+		o.logLevelLogger = l
+		o.logLevelMiddlewares = middlewares
+	}
+}
+
+// WithLogBuffering 启用请求级日志缓冲：请求内的 Debug/Info 日志先暂存在请求 context 中，
+// 仅当请求返回 5xx、存在 c.Errors 或耗时超过 threshold 时写出，否则丢弃
+// threshold <= 0 时使用慢请求阈值；opts 可调整缓冲的级别和容量
+func WithLogBuffering(threshold time.Duration, opts ...logger.BufferOption) Option {
+	return func(o *options) {
+		o.logBuffering = true
+		o.logBufferThreshold = threshold
+		o.logBufferOptions = opts
+	}
+}
@@ -3,6 +3,7 @@ package web
 import (
 	"context"
 
+	"github.com/Si40Code/kit/logger"
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
@@ -51,14 +52,26 @@ func New(opts ...Option) *Server {
 		engine.Use(otelgin.Middleware(options.serviceName))
 	}
 
-	// 4. 请求日志和指标中间件（核心）
+	// 4. 请求上下文中间件（为 handler 中的日志提供 client_ip、route 字段）
+	engine.Use(server.requestContextMiddleware())
+
+	// 5. 请求日志和指标中间件（核心）
 	engine.Use(server.loggingMiddleware())
 
-	// 5. 用户自定义中间件
+	// 6. 用户自定义中间件
 	for _, mw := range options.middlewares {
 		engine.Use(mw)
 	}
 
+	// 日志级别管理接口
+	if options.logLevelPath != "" && options.logLevelLogger != nil {
+		handlers := append(append([]gin.HandlerFunc(nil), options.logLevelMiddlewares...),
+			gin.WrapH(logger.LevelHandler(options.logLevelLogger)))
+		engine.GET(options.logLevelPath, handlers...)
+		engine.PUT(options.logLevelPath, handlers...)
+		engine.POST(options.logLevelPath, handlers...)
+	}
+
 	return server
 }
 